@@ -3,7 +3,7 @@ package cmd
 import (
 	"github.com/Daaaai0809/swagen-v2/fetcher"
 	"github.com/Daaaai0809/swagen-v2/handler/api"
-	"github.com/Daaaai0809/swagen-v2/input"
+	"github.com/Daaaai0809/swagen-v2/input/prompt"
 	"github.com/Daaaai0809/swagen-v2/validator"
 	"github.com/spf13/cobra"
 )
@@ -18,7 +18,7 @@ var apiCmd = &cobra.Command{
 			return err
 		}
 
-		inputMethods := input.NewInputMethods()
+		inputMethods := prompt.NewInputMethods()
 		validation := validator.NewInputValidator()
 		directoryFetcher := fetcher.NewDirectoryFetcher(inputMethods, validation)
 		apiHandler := api.NewAPIHandler(inputMethods, validation, fetcher.NewFileFetcher(), directoryFetcher)
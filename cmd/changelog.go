@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/Daaaai0809/swagen-v2/handler/changelog"
+	"github.com/spf13/cobra"
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog --from <revision> [--to <revision>]",
+	Short: "Generate a changelog between two versions of the spec",
+	Long: `Compare two versions of the spec the same way diff does and write a Markdown
+changelog grouped into added, changed, deprecated and removed endpoints and fields,
+with the operationId and summary of every affected operation. Breaking changes
+are marked.
+
+--from and --to are git revisions (e.g. v1.2.0 and HEAD) or directories holding
+the SWAGEN_* directories. --json also writes the changelog as JSON.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := cmd.Flags().GetString("from")
+		if err != nil {
+			return err
+		}
+
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return err
+		}
+
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		jsonPath, err := cmd.Flags().GetString("json")
+		if err != nil {
+			return err
+		}
+
+		changelogHandler := changelog.NewChangelogHandler(out, jsonPath)
+		if err := changelogHandler.HandleChangelogCommand(from, to); err != nil {
+			cmd.PrintErrf("[ERROR] Generating changelog: %v\n", err)
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	changelogCmd.Flags().String("from", "", "Old version: a git revision or a directory")
+	changelogCmd.Flags().String("to", changelog.DEFAULT_TO, "New version: a git revision or a directory")
+	changelogCmd.Flags().String("out", "", "Markdown file to write (default: stdout)")
+	changelogCmd.Flags().String("json", "", "JSON file to write the changelog to as well")
+	_ = changelogCmd.MarkFlagRequired("from")
+
+	rootCmd.AddCommand(changelogCmd)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"github.com/Daaaai0809/swagen-v2/handler/diff"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> [<new>]",
+	Short: "Detect breaking changes between two versions of the spec",
+	Long: `Compare the operations of two versions of the spec and classify every change as
+breaking or non-breaking for the clients of the API.
+
+<old> and <new> are each a directory holding the SWAGEN_* directories, or a git
+revision of the current repository (e.g. main, HEAD~1 or v1.2.0) whose files are
+read with git show. <new> defaults to the working tree.
+
+Operations are matched by method and URL path. Their parameters, request bodies
+and responses are compared with every $ref resolved: required lists, types,
+formats, nullable, enums and constraints. Changes clients can no longer send or
+no longer rely on, like a new required request field or a removed response field,
+are breaking. The command exits with status 1 when any change is breaking.`,
+	Args: cobra.RangeArgs(1, 2),
+	// breaking changes are not usage errors
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		newSource := diff.DEFAULT_NEW_SOURCE
+		if len(args) == 2 {
+			newSource = args[1]
+		}
+
+		diffHandler := diff.NewDiffHandler(format)
+		if err := diffHandler.HandleDiffCommand(args[0], newSource); err != nil {
+			cmd.PrintErrf("[ERROR] Comparing specs: %v\n", err)
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	diffCmd.Flags().String("format", diff.FORMAT_TEXT, "Output format: text or json")
+
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"github.com/Daaaai0809/swagen-v2/handler/docs"
+	"github.com/spf13/cobra"
+)
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate HTML or Markdown API documentation",
+	Long: `Generate the API reference of the path files: every operation grouped by tag with
+its parameters, request body and responses, and an index of the models and schemas.
+$refs are resolved into field tables with the type, format, required, nullable and
+example of each field, and every model links to the operations which use it.
+
+--format html writes a single self-contained index.html which needs no network,
+--format markdown writes index.md.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		title, err := cmd.Flags().GetString("title")
+		if err != nil {
+			return err
+		}
+
+		docsHandler := docs.NewDocsHandler(out)
+		if err := docsHandler.HandleDocsCommand(format, title); err != nil {
+			cmd.PrintErrf("[ERROR] Generating docs: %v\n", err)
+			return err
+		}
+		cmd.Println("[INFO] Docs generated successfully.")
+		return nil
+	},
+}
+
+func init() {
+	docsCmd.Flags().String("format", docs.FORMAT_HTML, "Output format: html or markdown")
+	docsCmd.Flags().String("out", "docs", "Output directory")
+	docsCmd.Flags().String("title", "", "Title of the documentation (default: API Reference)")
+
+	rootCmd.AddCommand(docsCmd)
+}
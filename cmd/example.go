@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/Daaaai0809/swagen-v2/handler/example"
+	"github.com/spf13/cobra"
+)
+
+var exampleCmd = &cobra.Command{
+	Use:   "example <file>#<pointer>",
+	Short: "Generate an example JSON instance of a schema",
+	Long: `Generate a JSON instance valid against the schema at <file>#<pointer>, e.g.
+model/user.yaml, schema/user.yaml#/GetUserResponse or
+api/users.yaml#/get/responses/200/content/application~1json/schema.
+
+$refs are followed across files, and explicit example values are used as they are.
+A path file or an operation (api/users.yaml#/get) gives an example of every
+parameter, the request body and every response.
+
+The output only depends on --seed, so it can be committed as a fixture.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		seed, err := cmd.Flags().GetInt64("seed")
+		if err != nil {
+			return err
+		}
+
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		exampleHandler := example.NewExampleHandler(seed, out)
+		if err := exampleHandler.HandleExampleCommand(args[0]); err != nil {
+			cmd.PrintErrf("[ERROR] Generating example: %v\n", err)
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	exampleCmd.Flags().Int64("seed", example.DEFAULT_SEED, "Seed of the synthesized values")
+	exampleCmd.Flags().String("out", "", "File to write the example to (default: stdout)")
+
+	rootCmd.AddCommand(exampleCmd)
+}
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"github.com/Daaaai0809/swagen-v2/handler/gen"
+	"github.com/spf13/cobra"
+)
+
+var genCmd = &cobra.Command{
+	Use:   "gen",
+	Short: "Generate code from the models, schemas and paths",
+	Long: `Generate code from the model, schema and path files.
+$refs are resolved across files; the generated files are written under --out.`,
+}
+
+var genGoCmd = &cobra.Command{
+	Use:   "go",
+	Short: "Generate Go types with json tags",
+	Long: `Generate a Go struct for every model and schema root, one file per source file.
+Optional and nullable fields become pointers, date-time becomes time.Time and
+enums get a named type with constants.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		packageName, err := cmd.Flags().GetString("package")
+		if err != nil {
+			return err
+		}
+
+		genHandler := gen.NewGenHandler(out)
+		if err := genHandler.HandleGoCommand(packageName); err != nil {
+			cmd.PrintErrf("[ERROR] Generating Go types: %v\n", err)
+			return err
+		}
+		cmd.Println("[INFO] Go types generated successfully.")
+		return nil
+	},
+}
+
+var genServerCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Generate a Go net/http server stub from the path files",
+	Long: `Generate the Go types and a server file for the operations of the path files.
+The server file has a Server interface with one method per operationId, an input
+struct per operation holding its decoded parameters and body, a response helper
+per documented status code and RegisterRoutes which registers every operation on
+a net/http ServeMux with method and path patterns (Go 1.22 or later).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		packageName, err := cmd.Flags().GetString("package")
+		if err != nil {
+			return err
+		}
+
+		genHandler := gen.NewGenHandler(out)
+		if err := genHandler.HandleServerCommand(packageName); err != nil {
+			cmd.PrintErrf("[ERROR] Generating server: %v\n", err)
+			return err
+		}
+		cmd.Println("[INFO] Server generated successfully.")
+		return nil
+	},
+}
+
+var genClientCmd = &cobra.Command{
+	Use:   "client",
+	Short: "Generate a typed Go HTTP client from the path files",
+	Long: `Generate the Go types and a client file for the operations of the path files.
+The client has one method per operation which builds the URL from the path
+parameters, encodes the query, header and cookie parameters, marshals the request
+body for its media type and decodes every documented status code into its typed
+response. Statuses the operation does not document return an UnexpectedStatusError.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		packageName, err := cmd.Flags().GetString("package")
+		if err != nil {
+			return err
+		}
+
+		genHandler := gen.NewGenHandler(out)
+		if err := genHandler.HandleClientCommand(packageName); err != nil {
+			cmd.PrintErrf("[ERROR] Generating client: %v\n", err)
+			return err
+		}
+		cmd.Println("[INFO] Client generated successfully.")
+		return nil
+	},
+}
+
+var genTSCmd = &cobra.Command{
+	Use:   "ts",
+	Short: "Generate TypeScript types",
+	Long: `Generate a TypeScript interface or type alias for every model and schema root.
+Modules mirror the model/schema directories and import each other for cross-file $refs.
+Fields not listed in required are optional, nullable adds | null and enums become
+string literal unions.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		genHandler := gen.NewGenHandler(out)
+		if err := genHandler.HandleTSCommand(); err != nil {
+			cmd.PrintErrf("[ERROR] Generating TypeScript types: %v\n", err)
+			return err
+		}
+		cmd.Println("[INFO] TypeScript types generated successfully.")
+		return nil
+	},
+}
+
+var genZodCmd = &cobra.Command{
+	Use:   "zod",
+	Short: "Generate Zod schemas",
+	Long: `Generate a Zod schema and its inferred type for every model and schema root.
+Modules mirror the model/schema directories and import each other for cross-file $refs.
+Formats become email/uuid/datetime/url checks, nullable adds .nullable() and fields
+not listed in required get .optional().`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		genHandler := gen.NewGenHandler(out)
+		if err := genHandler.HandleZodCommand(); err != nil {
+			cmd.PrintErrf("[ERROR] Generating Zod schemas: %v\n", err)
+			return err
+		}
+		cmd.Println("[INFO] Zod schemas generated successfully.")
+		return nil
+	},
+}
+
+var genPythonCmd = &cobra.Command{
+	Use:   "python",
+	Short: "Generate pydantic v2 models",
+	Long: `Generate a pydantic v2 BaseModel for every model and schema root.
+Modules mirror the model/schema directories and import each other for cross-file $refs.
+Formats become datetime/UUID/EmailStr, nullable becomes Optional and fields not
+listed in required default to None.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		genHandler := gen.NewGenHandler(out)
+		if err := genHandler.HandlePythonCommand(); err != nil {
+			cmd.PrintErrf("[ERROR] Generating pydantic models: %v\n", err)
+			return err
+		}
+		cmd.Println("[INFO] Pydantic models generated successfully.")
+		return nil
+	},
+}
+
+var genProtoCmd = &cobra.Command{
+	Use:   "proto",
+	Short: "Generate proto3 messages",
+	Long: `Generate a proto3 message for every model and schema root, one .proto file per source file.
+Field numbers are stored in a lock file (default: <out>/swagen.proto.lock.yaml) so
+re-generation never renumbers; numbers of removed fields are reserved.
+date-time becomes google.protobuf.Timestamp, arrays become repeated fields and
+cross-file $refs become imported messages.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		packageName, err := cmd.Flags().GetString("package")
+		if err != nil {
+			return err
+		}
+
+		lockPath, err := cmd.Flags().GetString("lock")
+		if err != nil {
+			return err
+		}
+
+		genHandler := gen.NewGenHandler(out)
+		if err := genHandler.HandleProtoCommand(packageName, lockPath); err != nil {
+			cmd.PrintErrf("[ERROR] Generating proto messages: %v\n", err)
+			return err
+		}
+		cmd.Println("[INFO] Proto messages generated successfully.")
+		return nil
+	},
+}
+
+var genJSONSchemaCmd = &cobra.Command{
+	Use:   "jsonschema",
+	Short: "Generate JSON Schema 2020-12 documents",
+	Long: `Generate one JSON Schema 2020-12 document per model and schema root.
+Each document gets an $id under --base-uri, nullable becomes a type union with "null"
+and relative file refs are rewritten to the $id of the referenced document.
+With --dereference every $ref is inlined so each document stands alone.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		baseURI, err := cmd.Flags().GetString("base-uri")
+		if err != nil {
+			return err
+		}
+
+		dereference, err := cmd.Flags().GetBool("dereference")
+		if err != nil {
+			return err
+		}
+
+		genHandler := gen.NewGenHandler(out)
+		if err := genHandler.HandleJSONSchemaCommand(baseURI, dereference); err != nil {
+			cmd.PrintErrf("[ERROR] Generating JSON Schema: %v\n", err)
+			return err
+		}
+		cmd.Println("[INFO] JSON Schema generated successfully.")
+		return nil
+	},
+}
+
+var genTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Render user-defined text/templates against the project",
+	Long: `Render Go text/template files against the resolved data model of the project:
+its models, schemas, named types and operations with their parameters, bodies and
+responses. Each template is rendered to <out>/<template name without .tmpl>, templates
+whose name starts with "_" only define blocks shared by the others.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		templates, err := cmd.Flags().GetStringSlice("template")
+		if err != nil {
+			return err
+		}
+
+		genHandler := gen.NewGenHandler(out)
+		if err := genHandler.HandleTemplateCommand(templates); err != nil {
+			cmd.PrintErrf("[ERROR] Rendering templates: %v\n", err)
+			return err
+		}
+		cmd.Println("[INFO] Templates rendered successfully.")
+		return nil
+	},
+}
+
+func init() {
+	genCmd.PersistentFlags().String("out", "", "Output directory")
+	_ = genCmd.MarkPersistentFlagRequired("out")
+
+	genGoCmd.Flags().String("package", "", "Go package name (default: name of the output directory)")
+	genServerCmd.Flags().String("package", "", "Go package name (default: name of the output directory)")
+	genClientCmd.Flags().String("package", "", "Go package name (default: name of the output directory)")
+	genProtoCmd.Flags().String("package", "", "Proto package name (default: api)")
+	genProtoCmd.Flags().String("lock", "", "Field number lock file (default: <out>/swagen.proto.lock.yaml)")
+	genJSONSchemaCmd.Flags().String("base-uri", "", "Base URI of the $ids (default: https://swagen.local/schemas/)")
+	genJSONSchemaCmd.Flags().Bool("dereference", false, "Inline every $ref so each document stands alone")
+	genTemplateCmd.Flags().StringSlice("template", nil, "Template files or glob patterns, repeatable")
+	_ = genTemplateCmd.MarkFlagRequired("template")
+
+	genCmd.AddCommand(genGoCmd)
+	genCmd.AddCommand(genServerCmd)
+	genCmd.AddCommand(genClientCmd)
+	genCmd.AddCommand(genTSCmd)
+	genCmd.AddCommand(genZodCmd)
+	genCmd.AddCommand(genPythonCmd)
+	genCmd.AddCommand(genProtoCmd)
+	genCmd.AddCommand(genJSONSchemaCmd)
+	genCmd.AddCommand(genTemplateCmd)
+	rootCmd.AddCommand(genCmd)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"github.com/Daaaai0809/swagen-v2/handler/graph"
+	"github.com/spf13/cobra"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the $ref dependency graph as DOT or Mermaid",
+	Long: `Scan every model, schema and path file, extract each $ref with its file and
+pointer and render the dependency graph in Graphviz DOT or Mermaid syntax.
+
+--granularity file draws a node per file and an edge per referencing file pair,
+--granularity field draws a node per $ref location and $ref target, grouped by file.
+--from <file> keeps what is reachable from the file, --to <file> keeps what
+reaches it, e.g. every schema and endpoint depending on model/user.yaml.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		granularity, err := cmd.Flags().GetString("granularity")
+		if err != nil {
+			return err
+		}
+
+		from, err := cmd.Flags().GetString("from")
+		if err != nil {
+			return err
+		}
+
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return err
+		}
+
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		graphHandler := graph.NewGraphHandler(out)
+		if err := graphHandler.HandleGraphCommand(format, granularity, from, to); err != nil {
+			cmd.PrintErrf("[ERROR] Generating graph: %v\n", err)
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	graphCmd.Flags().String("format", graph.FORMAT_DOT, "Output format: dot or mermaid")
+	graphCmd.Flags().String("granularity", graph.GRANULARITY_FILE, "Node granularity: file or field")
+	graphCmd.Flags().String("from", "", "Keep only what is reachable from this file")
+	graphCmd.Flags().String("to", "", "Keep only what reaches this file")
+	graphCmd.Flags().String("out", "", "File to write the graph to (default: stdout)")
+
+	rootCmd.AddCommand(graphCmd)
+}
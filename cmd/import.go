@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/Daaaai0809/swagen-v2/handler/openapi"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <openapi.yaml>",
+	Short: "Split an existing OpenAPI document into swagen files",
+	Long: `Split a monolithic OpenAPI document into the swagen directory layout.
+components/schemas become model and schema files, each path item becomes a path file
+under SWAGEN_API_PATH, and internal #/components refs are rewritten into relative file refs.
+Anything that could not be placed is reported at the end.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		openAPIHandler := openapi.NewOpenAPIHandler()
+		if err := openAPIHandler.HandleImportCommand(args[0], force); err != nil {
+			cmd.PrintErrf("[ERROR] Importing OpenAPI document: %v\n", err)
+			return err
+		}
+		cmd.Println("[INFO] OpenAPI document imported successfully.")
+		return nil
+	},
+}
+
+func init() {
+	importCmd.Flags().Bool("force", false, "Overwrite existing files")
+
+	rootCmd.AddCommand(importCmd)
+}
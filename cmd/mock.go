@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/Daaaai0809/swagen-v2/generator/example"
+	"github.com/Daaaai0809/swagen-v2/handler/mock"
+	"github.com/spf13/cobra"
+)
+
+var mockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Serve a mock server of the path files",
+	Long: `Serve every operation of the path files on a local port.
+
+Responses are built from the example values of their schemas, or synthesized
+from the schemas (formats like uuid, email and date-time get realistic values).
+The same --seed always gives the same responses.
+
+A "Prefer: code=404" request header selects the documented response to serve,
+otherwise the first 2XX response is served. JSON request bodies are validated
+against the requestBody schema and rejected with an RFC 7807 problem.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		port, err := cmd.Flags().GetInt("port")
+		if err != nil {
+			return err
+		}
+
+		seed, err := cmd.Flags().GetInt64("seed")
+		if err != nil {
+			return err
+		}
+
+		mockHandler := mock.NewMockHandler(port, seed)
+		if err := mockHandler.HandleMockCommand(); err != nil {
+			cmd.PrintErrf("[ERROR] Serving mock server: %v\n", err)
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	mockCmd.Flags().Int("port", 8080, "Port to listen on")
+	mockCmd.Flags().Int64("seed", example.DEFAULT_SEED, "Seed of the synthesized values")
+
+	rootCmd.AddCommand(mockCmd)
+}
@@ -4,9 +4,11 @@ Copyright © 2025 NAME HERE dai.tsuruga0809@gmail.com
 package cmd
 
 import (
+	"errors"
+
 	"github.com/Daaaai0809/swagen-v2/fetcher"
 	"github.com/Daaaai0809/swagen-v2/handler/model"
-	"github.com/Daaaai0809/swagen-v2/input"
+	"github.com/Daaaai0809/swagen-v2/input/prompt"
 	"github.com/Daaaai0809/swagen-v2/validator"
 	"github.com/spf13/cobra"
 )
@@ -16,7 +18,7 @@ var modelCmd = &cobra.Command{
 	Use:   "model",
 	Short: "Generate model schema",
 	Run: func(cmd *cobra.Command, args []string) {
-		inputMethods := input.NewInputMethods()
+		inputMethods := prompt.NewInputMethods()
 		validation := validator.NewInputValidator()
 		directoryFetcher := fetcher.NewDirectoryFetcher(inputMethods, validation)
 		modelHandler := model.NewModelHandler(inputMethods, validation, directoryFetcher)
@@ -28,6 +30,71 @@ var modelCmd = &cobra.Command{
 	},
 }
 
+// modelImportCmd represents the model import command
+var modelImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import model schema from an existing source",
+	Long: `Infer a model schema from an existing source:
+  --json sample.json                      a sample JSON payload
+  --go ./internal/domain --type User      a Go struct definition (and the structs it uses)
+  --sql schema.sql                        CREATE TABLE statements (PostgreSQL / MySQL), one model per table`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonFile, err := cmd.Flags().GetString("json")
+		if err != nil {
+			return err
+		}
+
+		goDir, err := cmd.Flags().GetString("go")
+		if err != nil {
+			return err
+		}
+
+		goType, err := cmd.Flags().GetString("type")
+		if err != nil {
+			return err
+		}
+
+		sqlFile, err := cmd.Flags().GetString("sql")
+		if err != nil {
+			return err
+		}
+
+		inputMethods := prompt.NewInputMethods()
+		validation := validator.NewInputValidator()
+		directoryFetcher := fetcher.NewDirectoryFetcher(inputMethods, validation)
+		modelHandler := model.NewModelHandler(inputMethods, validation, directoryFetcher)
+
+		switch {
+		case jsonFile != "":
+			if err := modelHandler.HandleImportJSONCommand(jsonFile); err != nil {
+				cmd.PrintErrf("[ERROR] Importing model schema: %v\n", err)
+				return err
+			}
+		case goDir != "":
+			if err := modelHandler.HandleImportGoCommand(goDir, goType); err != nil {
+				cmd.PrintErrf("[ERROR] Importing model schema: %v\n", err)
+				return err
+			}
+		case sqlFile != "":
+			if err := modelHandler.HandleImportSQLCommand(sqlFile); err != nil {
+				cmd.PrintErrf("[ERROR] Importing model schema: %v\n", err)
+				return err
+			}
+		default:
+			return errors.New("[ERROR] specify a source to import from (--json, --go or --sql)")
+		}
+
+		cmd.Println("[INFO] Model schema imported successfully.")
+		return nil
+	},
+}
+
 func init() {
+	modelImportCmd.Flags().String("json", "", "Sample JSON payload to infer the model from")
+	modelImportCmd.Flags().String("go", "", "Go package directory to import struct types from")
+	modelImportCmd.Flags().String("type", "", "Go struct type to import (used with --go)")
+	modelImportCmd.Flags().String("sql", "", "SQL file with CREATE TABLE statements to import")
+
+	modelCmd.AddCommand(modelImportCmd)
 	rootCmd.AddCommand(modelCmd)
 }
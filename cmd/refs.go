@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"github.com/Daaaai0809/swagen-v2/handler/refs"
+	"github.com/spf13/cobra"
+)
+
+var refsCmd = &cobra.Command{
+	Use:   "refs",
+	Short: "Find the usages of models and schemas through $refs",
+}
+
+var refsWhoUsesCmd = &cobra.Command{
+	Use:   "who-uses <file>#<pointer>",
+	Short: "List every location referencing a model, schema or field",
+	Long: `List every $ref location which uses <file>#<pointer>, e.g.
+model/user.yaml#/properties/email: $refs to the location itself, to an ancestor
+of it like model/user.yaml#, or to a part of it. The locations using it through
+other schemas, like an endpoint returning a schema wrapping the model, follow
+marked as indirect.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refsHandler := refs.NewRefsHandler()
+		if err := refsHandler.HandleWhoUsesCommand(args[0]); err != nil {
+			cmd.PrintErrf("[ERROR] Finding usages: %v\n", err)
+			return err
+		}
+		return nil
+	},
+}
+
+var refsOrphansCmd = &cobra.Command{
+	Use:   "orphans",
+	Short: "List models and schemas nothing references",
+	Long: `List the model files, schema files and schema roots which no $ref points at,
+so dead definitions can be cleaned up. A schema file is listed as a whole when
+none of its roots is referenced. References of a schema to itself do not count.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refsHandler := refs.NewRefsHandler()
+		if err := refsHandler.HandleOrphansCommand(); err != nil {
+			cmd.PrintErrf("[ERROR] Finding orphans: %v\n", err)
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	refsCmd.AddCommand(refsWhoUsesCmd)
+	refsCmd.AddCommand(refsOrphansCmd)
+	rootCmd.AddCommand(refsCmd)
+}
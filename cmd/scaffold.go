@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/handler/scaffold"
+	"github.com/Daaaai0809/swagen-v2/input/prompt"
+	"github.com/Daaaai0809/swagen-v2/validator"
+	"github.com/spf13/cobra"
+)
+
+var scaffoldCmd = &cobra.Command{
+	Use:   "scaffold <resource>",
+	Short: "Generate a CRUD resource",
+	Long: `Generate the model, Create/Update/Response schemas and the collection/item path files
+with GET/POST/PUT/PATCH/DELETE operations of a REST resource in one go.
+
+Fields are given as name:type[:format] (e.g. --fields "name:string,email:string:email").
+When --fields is omitted they are read interactively.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fields, err := cmd.Flags().GetString("fields")
+		if err != nil {
+			return err
+		}
+
+		plural, err := cmd.Flags().GetString("plural")
+		if err != nil {
+			return err
+		}
+
+		inputMethods := prompt.NewInputMethods()
+		validation := validator.NewInputValidator()
+		directoryFetcher := fetcher.NewDirectoryFetcher(inputMethods, validation)
+		scaffoldHandler := scaffold.NewScaffoldHandler(inputMethods, validation, directoryFetcher)
+		if err := scaffoldHandler.HandleScaffoldCommand(args[0], plural, fields); err != nil {
+			cmd.PrintErrf("[ERROR] Scaffolding resource: %v\n", err)
+			return err
+		}
+		cmd.Println("[INFO] Resource scaffolded successfully.")
+		return nil
+	},
+}
+
+func init() {
+	scaffoldCmd.Flags().String("fields", "", "Comma separated fields as name:type[:format]")
+	scaffoldCmd.Flags().String("plural", "", "Plural name used for the path files (default: <resource>s)")
+
+	rootCmd.AddCommand(scaffoldCmd)
+}
@@ -3,7 +3,7 @@ package cmd
 import (
 	"github.com/Daaaai0809/swagen-v2/fetcher"
 	"github.com/Daaaai0809/swagen-v2/handler/schema"
-	"github.com/Daaaai0809/swagen-v2/input"
+	"github.com/Daaaai0809/swagen-v2/input/prompt"
 	"github.com/Daaaai0809/swagen-v2/validator"
 	"github.com/spf13/cobra"
 )
@@ -13,7 +13,7 @@ var schemaCmd = &cobra.Command{
 	Short: "Generate a Request/Response Schema file",
 	Long:  `Interactively generate a schema file for your models.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		inputMethods := input.NewInputMethods()
+		inputMethods := prompt.NewInputMethods()
 		validation := validator.NewInputValidator()
 		directoryFetcher := fetcher.NewDirectoryFetcher(inputMethods, validation)
 		schemaHandler := schema.NewSchemaHandler(inputMethods, validation, fetcher.NewFileFetcher(), directoryFetcher)
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"github.com/Daaaai0809/swagen-v2/handler/verify"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify --har <traffic.har>",
+	Short: "Verify recorded HTTP traffic against the path files",
+	Long: `Match every request of a HAR file to a path file operation by method and
+URL template, then validate its path, query, header and cookie parameters, its
+request body and its response status and body against the documented schemas.
+
+Undocumented endpoints, undocumented status codes and schema violations are
+reported, and the command exits with status 1 when any is found.`,
+	Args: cobra.NoArgs,
+	// violations are not usage errors
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		har, err := cmd.Flags().GetString("har")
+		if err != nil {
+			return err
+		}
+
+		basePath, err := cmd.Flags().GetString("base-path")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		verifyHandler := verify.NewVerifyHandler(basePath, format)
+		if err := verifyHandler.HandleVerifyCommand(har); err != nil {
+			cmd.PrintErrf("[ERROR] Verifying traffic: %v\n", err)
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	verifyCmd.Flags().String("har", "", "HAR file with the recorded traffic")
+	verifyCmd.Flags().String("base-path", "", "URL path prefix which is not part of the path files (e.g. /api/v1)")
+	verifyCmd.Flags().String("format", verify.FORMAT_TEXT, "Report format: text or json")
+	_ = verifyCmd.MarkFlagRequired("har")
+
+	rootCmd.AddCommand(verifyCmd)
+}
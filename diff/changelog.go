@@ -0,0 +1,134 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Changelog is a diff report grouped for release notes: by kind of change,
+// then into changed endpoints and changed fields of endpoints. Parameters,
+// request bodies, responses and media types are listed with the fields.
+type Changelog struct {
+	From     string     `json:"from"`
+	To       string     `json:"to"`
+	Breaking int        `json:"breaking"`
+	Sections []*Section `json:"sections"`
+}
+
+// Section holds the changes of one kind
+type Section struct {
+	Kind      string              `json:"kind"`
+	Endpoints []Change            `json:"endpoints"`
+	Fields    []*OperationChanges `json:"fields"`
+}
+
+// OperationChanges are the changes below a single operation
+type OperationChanges struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	OperationID string   `json:"operationId,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Changes     []Change `json:"changes"`
+}
+
+// changelogKinds is the order of the sections
+var changelogKinds = []string{CHANGE_ADDED, CHANGE_CHANGED, CHANGE_DEPRECATED, CHANGE_REMOVED}
+
+func NewChangelog(report *Report, from, to string) *Changelog {
+	changelog := &Changelog{From: from, To: to, Breaking: report.Breaking}
+	for _, kind := range changelogKinds {
+		section := &Section{Kind: kind, Endpoints: []Change{}, Fields: []*OperationChanges{}}
+		byOperation := map[string]*OperationChanges{}
+		for _, change := range report.Changes {
+			if change.Kind != kind {
+				continue
+			}
+			if change.Element == ELEMENT_OPERATION {
+				section.Endpoints = append(section.Endpoints, change)
+				continue
+			}
+			key := change.Method + " " + change.Path
+			op, ok := byOperation[key]
+			if !ok {
+				op = &OperationChanges{Method: change.Method, Path: change.Path, OperationID: change.OperationID, Summary: change.Summary}
+				byOperation[key] = op
+				section.Fields = append(section.Fields, op)
+			}
+			op.Changes = append(op.Changes, change)
+		}
+		changelog.Sections = append(changelog.Sections, section)
+	}
+	return changelog
+}
+
+// Markdown renders the changelog, leaving out empty sections
+func (c *Changelog) Markdown() []byte {
+	var b strings.Builder
+	b.WriteString("# Changelog\n\n")
+	fmt.Fprintf(&b, "Changes from `%s` to `%s`.", c.From, c.To)
+	if c.Breaking > 0 {
+		fmt.Fprintf(&b, " **%d breaking change(s).**", c.Breaking)
+	}
+	b.WriteString("\n")
+
+	empty := true
+	for _, section := range c.Sections {
+		if len(section.Endpoints) == 0 && len(section.Fields) == 0 {
+			continue
+		}
+		empty = false
+		fmt.Fprintf(&b, "\n## %s\n", strings.ToUpper(section.Kind[:1])+section.Kind[1:])
+
+		if len(section.Endpoints) > 0 {
+			b.WriteString("\n### Endpoints\n\n")
+			for _, change := range section.Endpoints {
+				fmt.Fprintf(&b, "- %s%s", breakingLabel(change), operationLine(change.Method, change.Path, change.OperationID, change.Summary))
+				if change.Kind == CHANGE_CHANGED {
+					b.WriteString(": " + change.Message)
+				}
+				b.WriteString("\n")
+			}
+		}
+
+		if len(section.Fields) > 0 {
+			b.WriteString("\n### Fields\n\n")
+			for _, op := range section.Fields {
+				fmt.Fprintf(&b, "- %s\n", operationLine(op.Method, op.Path, op.OperationID, op.Summary))
+				for _, change := range op.Changes {
+					fmt.Fprintf(&b, "  - %s%s\n", breakingLabel(change), changeLine(change))
+				}
+			}
+		}
+	}
+	if empty {
+		b.WriteString("\nNo changes.\n")
+	}
+	return []byte(b.String())
+}
+
+// operationLine is e.g. "`GET /users` (`listUsers`) — List users"
+func operationLine(method, path, operationID, summary string) string {
+	line := fmt.Sprintf("`%s %s`", method, path)
+	if operationID != "" {
+		line += fmt.Sprintf(" (`%s`)", operationID)
+	}
+	if summary != "" {
+		line += " — " + summary
+	}
+	return line
+}
+
+func changeLine(change Change) string {
+	line := change.Location
+	if change.Field != "" {
+		line += fmt.Sprintf(" `%s`", change.Field)
+	}
+	return strings.TrimSpace(line) + ": " + change.Message
+}
+
+func breakingLabel(change Change) string {
+	if change.Breaking {
+		return "**Breaking:** "
+	}
+	return ""
+}
@@ -0,0 +1,382 @@
+// Package diff compares two versions of a swagen project operation by
+// operation and classifies every change as breaking or non-breaking for the
+// clients of the API.
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/handler/api"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+// Kinds of changes
+const (
+	CHANGE_ADDED      = "added"
+	CHANGE_REMOVED    = "removed"
+	CHANGE_CHANGED    = "changed"
+	CHANGE_DEPRECATED = "deprecated"
+)
+
+// Elements of an operation a change is about
+const (
+	ELEMENT_OPERATION    = "operation"
+	ELEMENT_PARAMETER    = "parameter"
+	ELEMENT_REQUEST_BODY = "request-body"
+	ELEMENT_RESPONSE     = "response"
+	ELEMENT_MEDIA_TYPE   = "media-type"
+	ELEMENT_FIELD        = "field"  // a property of an object, at any depth
+	ELEMENT_SCHEMA       = "schema" // the type, format, enum or constraints of a schema
+)
+
+var pathParamPattern = regexp.MustCompile(`\{[^}]*\}`)
+
+// Change is a difference between the old and the new version of an operation
+type Change struct {
+	Kind        string `json:"kind"`
+	Element     string `json:"element"`
+	Breaking    bool   `json:"breaking"`
+	Method      string `json:"method"` // upper-case
+	Path        string `json:"path"`
+	OperationID string `json:"operationId,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+	Location    string `json:"location,omitempty"` // e.g. "query parameter limit", "response 200 application/json"
+	Field       string `json:"field,omitempty"`    // property path below the location, e.g. lines[].sku
+	Message     string `json:"message"`
+}
+
+func (c Change) String() string {
+	s := c.Method + " " + c.Path
+	if c.Location != "" {
+		s += " " + c.Location
+	}
+	if c.Field != "" {
+		s += " " + c.Field
+	}
+	return s + ": " + c.Message
+}
+
+// Report is every change between two versions of a project
+type Report struct {
+	Changes  []Change `json:"changes"`
+	Breaking int      `json:"breaking"` // number of breaking changes
+}
+
+// Comparer compares the operations of two projects
+type Comparer struct {
+	Old *loader.Project
+	New *loader.Project
+}
+
+func NewComparer(oldProject, newProject *loader.Project) *Comparer {
+	return &Comparer{
+		Old: oldProject,
+		New: newProject,
+	}
+}
+
+// Compare returns the changes from the old to the new project. Operations are
+// matched by method and URL path, ignoring the names of path parameters.
+func Compare(oldProject, newProject *loader.Project) (*Report, error) {
+	return NewComparer(oldProject, newProject).Compare()
+}
+
+func (c *Comparer) Compare() (*Report, error) {
+	oldOperations, err := c.Old.Operations()
+	if err != nil {
+		return nil, err
+	}
+	newOperations, err := c.New.Operations()
+	if err != nil {
+		return nil, err
+	}
+
+	oldByKey := map[string]*loader.Operation{}
+	for _, op := range oldOperations {
+		oldByKey[operationKey(op)] = op
+	}
+
+	report := &Report{Changes: []Change{}}
+	matched := map[string]bool{}
+	for _, newOp := range newOperations {
+		key := operationKey(newOp)
+		oldOp, ok := oldByKey[key]
+		od := newOperationDiff(c, oldOp, newOp)
+		if !ok {
+			od.add(Change{Kind: CHANGE_ADDED, Element: ELEMENT_OPERATION, Message: "operation added"})
+		} else {
+			matched[key] = true
+			if err := od.compare(); err != nil {
+				return nil, err
+			}
+		}
+		report.Changes = append(report.Changes, od.changes...)
+	}
+	for _, oldOp := range oldOperations {
+		if matched[operationKey(oldOp)] {
+			continue
+		}
+		od := newOperationDiff(c, oldOp, nil)
+		od.add(Change{Kind: CHANGE_REMOVED, Element: ELEMENT_OPERATION, Breaking: true, Message: "operation removed"})
+		report.Changes = append(report.Changes, od.changes...)
+	}
+
+	sort.SliceStable(report.Changes, func(i, j int) bool {
+		return report.Changes[i].Path < report.Changes[j].Path
+	})
+	for _, change := range report.Changes {
+		if change.Breaking {
+			report.Breaking++
+		}
+	}
+	return report, nil
+}
+
+// operationKey identifies an operation across versions, e.g. "get /users/{}"
+func operationKey(op *loader.Operation) string {
+	return op.Method + " " + pathParamPattern.ReplaceAllString(op.Path, "{}")
+}
+
+// operationDiff collects the changes of a single operation
+type operationDiff struct {
+	comparer *Comparer
+	old      *loader.Operation
+	new      *loader.Operation
+	changes  []Change
+	seen     map[string]bool // schema pairs already compared, against $ref cycles
+}
+
+func newOperationDiff(c *Comparer, oldOp, newOp *loader.Operation) *operationDiff {
+	return &operationDiff{comparer: c, old: oldOp, new: newOp, seen: map[string]bool{}}
+}
+
+// add records a change with the method, path, operationId and summary of the
+// operation, taken from its new version when it has one
+func (od *operationDiff) add(change Change) {
+	op := od.new
+	if op == nil {
+		op = od.old
+	}
+	change.Method = strings.ToUpper(op.Method)
+	change.Path = op.Path
+	change.OperationID = op.API.OperationID
+	change.Summary = op.API.Summary
+	od.changes = append(od.changes, change)
+}
+
+func (od *operationDiff) compare() error {
+	if od.old.API.OperationID != od.new.API.OperationID {
+		od.add(Change{Kind: CHANGE_CHANGED, Element: ELEMENT_OPERATION, Message: fmt.Sprintf("operationId changed from %q to %q", od.old.API.OperationID, od.new.API.OperationID)})
+	}
+	if od.old.API.Deprecated != od.new.API.Deprecated {
+		od.add(deprecation(ELEMENT_OPERATION, "", "", "operation", od.new.API.Deprecated))
+	}
+	if err := od.compareParameters(); err != nil {
+		return err
+	}
+	if err := od.compareRequestBody(); err != nil {
+		return err
+	}
+	return od.compareResponses()
+}
+
+func (od *operationDiff) compareParameters() error {
+	oldParams := map[string]int{}
+	for i, param := range od.old.API.Parameters {
+		if param != nil {
+			oldParams[parameterKey(od.old, param)] = i
+		}
+	}
+
+	newKeys := map[string]bool{}
+	for i, param := range od.new.API.Parameters {
+		if param == nil {
+			continue
+		}
+		key := parameterKey(od.new, param)
+		newKeys[key] = true
+		location := param.In + " parameter " + param.Name
+		required := param.Required || param.In == constants.PARAM_IN_PATH
+
+		j, ok := oldParams[key]
+		if !ok {
+			message := "parameter added"
+			if required {
+				message = "required parameter added"
+			}
+			od.add(Change{Kind: CHANGE_ADDED, Element: ELEMENT_PARAMETER, Breaking: required, Location: location, Message: message})
+			continue
+		}
+
+		oldParam := od.old.API.Parameters[j]
+		if param.In == constants.PARAM_IN_PATH && oldParam.Name != param.Name {
+			// path parameters are matched by position, clients only fill in the value
+			od.add(Change{Kind: CHANGE_CHANGED, Element: ELEMENT_PARAMETER, Location: location, Message: fmt.Sprintf("path parameter renamed from %s to %s", oldParam.Name, param.Name)})
+		}
+		if oldParam.Deprecated != param.Deprecated {
+			od.add(deprecation(ELEMENT_PARAMETER, location, "", "parameter", param.Deprecated))
+		}
+		oldRequired := oldParam.Required || oldParam.In == constants.PARAM_IN_PATH
+		if oldRequired != required {
+			od.add(Change{Kind: CHANGE_CHANGED, Element: ELEMENT_PARAMETER, Breaking: required, Location: location, Message: requiredMessage("parameter", required)})
+		}
+		err := od.compareSchema(location, "", true,
+			operationTarget(od.old).Child("parameters", strconv.Itoa(j), "schema"), loader.ParameterSchema(oldParam),
+			operationTarget(od.new).Child("parameters", strconv.Itoa(i), "schema"), loader.ParameterSchema(param))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, param := range od.old.API.Parameters {
+		if param != nil && !newKeys[parameterKey(od.old, param)] {
+			// servers ignore what they no longer read
+			od.add(Change{Kind: CHANGE_REMOVED, Element: ELEMENT_PARAMETER, Location: param.In + " parameter " + param.Name, Message: "parameter removed"})
+		}
+	}
+	return nil
+}
+
+// parameterKey identifies a parameter of op. Path parameters are identified
+// by their position in the URL template, like operations, so renaming one
+// keeps it; header names are case-insensitive.
+func parameterKey(op *loader.Operation, param *api.Parameter) string {
+	if param.In == constants.PARAM_IN_PATH {
+		for i, segment := range pathParamPattern.FindAllString(op.Path, -1) {
+			if segment == "{"+param.Name+"}" {
+				return param.In + ":" + strconv.Itoa(i)
+			}
+		}
+	}
+	if param.In == constants.PARAM_IN_HEADER {
+		return param.In + ":" + strings.ToLower(param.Name)
+	}
+	return param.In + ":" + param.Name
+}
+
+func (od *operationDiff) compareRequestBody() error {
+	oldBody, newBody := od.old.API.RequestBody, od.new.API.RequestBody
+	location := "request body"
+	switch {
+	case oldBody == nil && newBody == nil:
+		return nil
+	case oldBody == nil:
+		message := "request body added"
+		if newBody.Required {
+			message = "required request body added"
+		}
+		od.add(Change{Kind: CHANGE_ADDED, Element: ELEMENT_REQUEST_BODY, Breaking: newBody.Required, Location: location, Message: message})
+		return nil
+	case newBody == nil:
+		od.add(Change{Kind: CHANGE_REMOVED, Element: ELEMENT_REQUEST_BODY, Location: location, Message: "request body removed"})
+		return nil
+	}
+
+	if oldBody.Required != newBody.Required {
+		od.add(Change{Kind: CHANGE_CHANGED, Element: ELEMENT_REQUEST_BODY, Breaking: newBody.Required, Location: location, Message: requiredMessage("request body", newBody.Required)})
+	}
+	return od.compareContent(location, true, oldBody.Content, newBody.Content,
+		operationTarget(od.old).Child("requestBody", "content"), operationTarget(od.new).Child("requestBody", "content"))
+}
+
+func (od *operationDiff) compareResponses() error {
+	for _, code := range unionKeys(od.old.API.Responses, od.new.API.Responses) {
+		oldResponse, inOld := od.old.API.Responses[code]
+		newResponse, inNew := od.new.API.Responses[code]
+		location := "response " + code
+		switch {
+		case !inOld:
+			od.add(Change{Kind: CHANGE_ADDED, Element: ELEMENT_RESPONSE, Location: location, Message: "response added"})
+			continue
+		case !inNew:
+			// clients rely on the success responses they were promised
+			od.add(Change{Kind: CHANGE_REMOVED, Element: ELEMENT_RESPONSE, Breaking: strings.HasPrefix(code, "2"), Location: location, Message: "response removed"})
+			continue
+		}
+
+		var oldContent, newContent map[string]*api.MediaType
+		if oldResponse != nil {
+			oldContent = oldResponse.Content
+		}
+		if newResponse != nil {
+			newContent = newResponse.Content
+		}
+		err := od.compareContent(location, false, oldContent, newContent,
+			operationTarget(od.old).Child("responses", code, "content"), operationTarget(od.new).Child("responses", code, "content"))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareContent compares the media types of a request body or response and
+// the schemas of the media types of both versions
+func (od *operationDiff) compareContent(location string, request bool, oldContent, newContent map[string]*api.MediaType, oldAt, newAt loader.Target) error {
+	for _, mediaType := range unionKeys(oldContent, newContent) {
+		oldMedia, inOld := oldContent[mediaType]
+		newMedia, inNew := newContent[mediaType]
+		switch {
+		case !inOld:
+			od.add(Change{Kind: CHANGE_ADDED, Element: ELEMENT_MEDIA_TYPE, Location: location, Message: "media type " + mediaType + " added"})
+			continue
+		case !inNew:
+			od.add(Change{Kind: CHANGE_REMOVED, Element: ELEMENT_MEDIA_TYPE, Breaking: true, Location: location, Message: "media type " + mediaType + " removed"})
+			continue
+		}
+
+		err := od.compareSchema(location+" "+mediaType, "", request,
+			oldAt.Child(mediaType, "schema"), schemaOf(oldMedia), newAt.Child(mediaType, "schema"), schemaOf(newMedia))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func schemaOf(media *api.MediaType) *handler.Property {
+	if media == nil {
+		return nil
+	}
+	return media.Schema
+}
+
+// deprecation is the change of a deprecated flag, undeprecating is a plain change
+func deprecation(element, location, field, what string, deprecated bool) Change {
+	if deprecated {
+		return Change{Kind: CHANGE_DEPRECATED, Element: element, Location: location, Field: field, Message: what + " deprecated"}
+	}
+	return Change{Kind: CHANGE_CHANGED, Element: element, Location: location, Field: field, Message: what + " no longer deprecated"}
+}
+
+func requiredMessage(what string, required bool) string {
+	if required {
+		return what + " became required"
+	}
+	return what + " became optional"
+}
+
+func operationTarget(op *loader.Operation) loader.Target {
+	return loader.Target{File: op.Document.File, Pointer: op.Pointer()}
+}
+
+// unionKeys returns the keys of both maps sorted
+func unionKeys[T any](a, b map[string]T) []string {
+	keys := []string{}
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
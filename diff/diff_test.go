@@ -0,0 +1,82 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/handler/api"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+func pathOperation(path string, params ...*api.Parameter) *loader.Operation {
+	return &loader.Operation{
+		Document: &loader.Document{File: "api" + path + ".yaml"},
+		Path:     path,
+		Method:   "get",
+		API:      &api.API{Parameters: params},
+	}
+}
+
+func pathParameter(name string) *api.Parameter {
+	return &api.Parameter{In: constants.PARAM_IN_PATH, Name: name, Required: true, Schema: &api.ParamSchema{Type: constants.STRING_TYPE}}
+}
+
+func TestCompareParameters(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new *loader.Operation
+		want     []string
+	}{
+		{
+			name: "path parameter renamed",
+			old:  pathOperation("/users/{id}", pathParameter("id")),
+			new:  pathOperation("/users/{userId}", pathParameter("userId")),
+			want: []string{"path parameter userId: path parameter renamed from id to userId"},
+		},
+		{
+			name: "path parameters swapped names",
+			old:  pathOperation("/users/{a}/posts/{b}", pathParameter("a"), pathParameter("b")),
+			new:  pathOperation("/users/{b}/posts/{a}", pathParameter("b"), pathParameter("a")),
+			want: []string{
+				"path parameter b: path parameter renamed from a to b",
+				"path parameter a: path parameter renamed from b to a",
+			},
+		},
+		{
+			name: "required query parameter added",
+			old:  pathOperation("/users"),
+			new:  pathOperation("/users", &api.Parameter{In: constants.PARAM_IN_QUERY, Name: "limit", Required: true, Schema: &api.ParamSchema{Type: constants.INTEGER_TYPE}}),
+			want: []string{"query parameter limit: required parameter added (breaking)"},
+		},
+		{
+			name: "header names are case-insensitive",
+			old:  pathOperation("/users", &api.Parameter{In: constants.PARAM_IN_HEADER, Name: "X-Request-Id", Schema: &api.ParamSchema{Type: constants.STRING_TYPE}}),
+			new:  pathOperation("/users", &api.Parameter{In: constants.PARAM_IN_HEADER, Name: "x-request-id", Schema: &api.ParamSchema{Type: constants.STRING_TYPE}}),
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if operationKey(tt.old) != operationKey(tt.new) {
+				t.Fatalf("operations %s and %s are not matched", tt.old.Path, tt.new.Path)
+			}
+			od := newOperationDiff(NewComparer(&loader.Project{}, &loader.Project{}), tt.old, tt.new)
+			if err := od.compareParameters(); err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, change := range od.changes {
+				line := change.Location + ": " + change.Message
+				if change.Breaking {
+					line += " (breaking)"
+				}
+				got = append(got, line)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+// compareSchema compares the old and new schema of a parameter, request body
+// or response with their $refs resolved. In requests the client writes the
+// value, so narrowing the schema breaks it; in responses the client reads it,
+// so widening the schema breaks it.
+func (od *operationDiff) compareSchema(location, field string, request bool, oldAt loader.Target, oldSchema *handler.Property, newAt loader.Target, newSchema *handler.Property) error {
+	element := ELEMENT_SCHEMA
+	if field != "" {
+		element = ELEMENT_FIELD
+	}
+	change := func(breaking bool, format string, args ...interface{}) {
+		od.add(Change{Kind: CHANGE_CHANGED, Element: element, Breaking: breaking, Location: location, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case oldSchema == nil && newSchema == nil:
+		return nil
+	case oldSchema == nil:
+		change(request, "schema added")
+		return nil
+	case newSchema == nil:
+		change(!request, "schema removed")
+		return nil
+	}
+
+	// deprecated may be written next to a $ref
+	oldDeprecated, newDeprecated := oldSchema.Deprecated, newSchema.Deprecated
+	oldAt, oldSchema, err := od.comparer.Old.Deref(oldAt, oldSchema)
+	if err != nil {
+		return err
+	}
+	newAt, newSchema, err = od.comparer.New.Deref(newAt, newSchema)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%s|%s|%s", location, oldAt, newAt)
+	if od.seen[key] {
+		return nil
+	}
+	od.seen[key] = true
+	defer delete(od.seen, key)
+
+	oldDeprecated = oldDeprecated || oldSchema.Deprecated
+	newDeprecated = newDeprecated || newSchema.Deprecated
+	if oldDeprecated != newDeprecated {
+		what := "schema"
+		if field != "" {
+			what = "field"
+		}
+		od.add(deprecation(element, location, field, what, newDeprecated))
+	}
+
+	if oldSchema.Type != newSchema.Type {
+		// integers are numbers, so the writer side may widen to number and the reader side narrow to integer
+		widened := oldSchema.Type == constants.INTEGER_TYPE && newSchema.Type == constants.NUMBER_TYPE
+		narrowed := oldSchema.Type == constants.NUMBER_TYPE && newSchema.Type == constants.INTEGER_TYPE
+		change(!(request && widened) && !(!request && narrowed), "type changed from %s to %s", typeName(oldSchema.Type), typeName(newSchema.Type))
+		return nil
+	}
+
+	if oldSchema.Format != newSchema.Format {
+		switch {
+		case oldSchema.Format == "":
+			change(request, "format %s added", newSchema.Format)
+		case newSchema.Format == "":
+			change(!request, "format %s removed", oldSchema.Format)
+		default:
+			change(true, "format changed from %s to %s", oldSchema.Format, newSchema.Format)
+		}
+	}
+
+	if oldSchema.Nullable != newSchema.Nullable {
+		if newSchema.Nullable {
+			change(!request, "became nullable")
+		} else {
+			change(request, "is no longer nullable")
+		}
+	}
+
+	compareEnum(request, oldSchema.Enum, newSchema.Enum, change)
+	compareConstraints(request, oldSchema, newSchema, change)
+
+	if err := od.compareProperties(location, field, request, oldAt, oldSchema, newAt, newSchema); err != nil {
+		return err
+	}
+
+	if oldSchema.Items != nil || newSchema.Items != nil {
+		return od.compareSchema(location, field+"[]", request,
+			oldAt.Child(fetcher.ITEMS_OPTION), oldSchema.Items, newAt.Child(fetcher.ITEMS_OPTION), newSchema.Items)
+	}
+	return nil
+}
+
+// compareEnum reports enum values clients may no longer send, or may now receive
+func compareEnum(request bool, oldEnum, newEnum []interface{}, change func(breaking bool, format string, args ...interface{})) {
+	switch {
+	case len(oldEnum) == 0 && len(newEnum) == 0:
+		return
+	case len(oldEnum) == 0:
+		change(request, "enum %s added", enumList(newEnum))
+		return
+	case len(newEnum) == 0:
+		change(!request, "enum %s removed", enumList(oldEnum))
+		return
+	}
+
+	if added := enumDifference(newEnum, oldEnum); len(added) > 0 {
+		change(!request, "enum values %s added", enumList(added))
+	}
+	if removed := enumDifference(oldEnum, newEnum); len(removed) > 0 {
+		change(request, "enum values %s removed", enumList(removed))
+	}
+}
+
+// compareConstraints reports tightened bounds and patterns of requests and
+// loosened ones of responses as breaking
+func compareConstraints(request bool, oldSchema, newSchema *handler.Property, change func(breaking bool, format string, args ...interface{})) {
+	bounds := []struct {
+		name     string
+		old, new *float64
+		upper    bool
+	}{
+		{"minLength", count(oldSchema.MinLength), count(newSchema.MinLength), false},
+		{"maxLength", count(oldSchema.MaxLength), count(newSchema.MaxLength), true},
+		{"minimum", oldSchema.Minimum, newSchema.Minimum, false},
+		{"maximum", oldSchema.Maximum, newSchema.Maximum, true},
+		{"minItems", count(oldSchema.MinItems), count(newSchema.MinItems), false},
+		{"maxItems", count(oldSchema.MaxItems), count(newSchema.MaxItems), true},
+	}
+	for _, bound := range bounds {
+		switch {
+		case bound.old == nil && bound.new == nil:
+		case bound.old == nil:
+			change(request, "%s %v added", bound.name, *bound.new)
+		case bound.new == nil:
+			change(!request, "%s %v removed", bound.name, *bound.old)
+		case *bound.old != *bound.new:
+			tightened := (*bound.new < *bound.old) == bound.upper
+			change(request == tightened, "%s changed from %v to %v", bound.name, *bound.old, *bound.new)
+		}
+	}
+
+	if oldSchema.Pattern != newSchema.Pattern {
+		switch {
+		case oldSchema.Pattern == "":
+			change(request, "pattern %s added", newSchema.Pattern)
+		case newSchema.Pattern == "":
+			change(!request, "pattern %s removed", oldSchema.Pattern)
+		default:
+			change(true, "pattern changed from %s to %s", oldSchema.Pattern, newSchema.Pattern)
+		}
+	}
+}
+
+// compareProperties reports added, removed and newly required or optional
+// properties and compares the properties of both versions
+func (od *operationDiff) compareProperties(location, field string, request bool, oldAt loader.Target, oldSchema *handler.Property, newAt loader.Target, newSchema *handler.Property) error {
+	oldRequired := map[string]bool{}
+	for _, name := range oldSchema.Required {
+		oldRequired[name] = true
+	}
+	newRequired := map[string]bool{}
+	for _, name := range newSchema.Required {
+		newRequired[name] = true
+	}
+
+	for _, name := range unionKeys(oldSchema.Properties, newSchema.Properties) {
+		oldProperty, inOld := oldSchema.Properties[name]
+		newProperty, inNew := newSchema.Properties[name]
+		path := fieldPath(field, name)
+		switch {
+		case !inOld:
+			message := "field added"
+			if newRequired[name] {
+				message = "required field added"
+			}
+			od.add(Change{Kind: CHANGE_ADDED, Element: ELEMENT_FIELD, Breaking: request && newRequired[name], Location: location, Field: path, Message: message})
+			continue
+		case !inNew:
+			// servers ignore fields they no longer read, clients miss fields they read
+			od.add(Change{Kind: CHANGE_REMOVED, Element: ELEMENT_FIELD, Breaking: !request, Location: location, Field: path, Message: "field removed"})
+			continue
+		}
+
+		if oldRequired[name] != newRequired[name] {
+			od.add(Change{Kind: CHANGE_CHANGED, Element: ELEMENT_FIELD, Breaking: newRequired[name] == request, Location: location, Field: path, Message: requiredMessage("field", newRequired[name])})
+		}
+		err := od.compareSchema(location, path, request,
+			oldAt.Child("properties", name), oldProperty, newAt.Child("properties", name), newProperty)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldPath joins property names, e.g. lines[] and sku give lines[].sku
+func fieldPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func typeName(schemaType string) string {
+	if schemaType == "" {
+		return "any"
+	}
+	return schemaType
+}
+
+// count returns an integer constraint as a bound, nil when unset (0)
+func count(n int) *float64 {
+	if n == 0 {
+		return nil
+	}
+	f := float64(n)
+	return &f
+}
+
+// enumDifference returns the values of a which are not in b
+func enumDifference(a, b []interface{}) []interface{} {
+	in := map[string]bool{}
+	for _, value := range b {
+		in[fmt.Sprint(value)] = true
+	}
+	values := []interface{}{}
+	for _, value := range a {
+		if !in[fmt.Sprint(value)] {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func enumList(values []interface{}) string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		out = append(out, fmt.Sprint(value))
+	}
+	return "[" + strings.Join(out, ", ") + "]"
+}
@@ -0,0 +1,187 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/handler/api"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+func object(required []string, properties map[string]*handler.Property) *handler.Property {
+	return &handler.Property{Type: constants.OBJECT_TYPE, Required: required, Properties: properties}
+}
+
+func str() *handler.Property {
+	return &handler.Property{Type: constants.STRING_TYPE}
+}
+
+func bound(f float64) *float64 {
+	return &f
+}
+
+// compare runs compareSchema on two inline schemas and formats the changes
+// as "<field>: <message>", suffixed with " (breaking)" when breaking
+func compare(t *testing.T, request bool, oldSchema, newSchema *handler.Property) []string {
+	t.Helper()
+	op := &loader.Operation{
+		Document: &loader.Document{File: "api/users.yaml"},
+		Path:     "/users",
+		Method:   "post",
+		API:      &api.API{},
+	}
+	od := newOperationDiff(NewComparer(&loader.Project{}, &loader.Project{}), op, op)
+	at := loader.Target{File: op.Document.File, Pointer: "/post/requestBody/content/application~1json/schema"}
+	if err := od.compareSchema("request body", "", request, at, oldSchema, at, newSchema); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := []string{}
+	for _, change := range od.changes {
+		line := change.Field + ": " + change.Message
+		if change.Breaking {
+			line += " (breaking)"
+		}
+		changes = append(changes, line)
+	}
+	return changes
+}
+
+func TestCompareSchemaBreaking(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new *handler.Property
+		request  []string // changes when the schema is written by clients
+		response []string // changes when the schema is read by clients
+	}{
+		{
+			name:     "required field added",
+			old:      object(nil, map[string]*handler.Property{"name": str()}),
+			new:      object([]string{"age"}, map[string]*handler.Property{"name": str(), "age": str()}),
+			request:  []string{"age: required field added (breaking)"},
+			response: []string{"age: required field added"},
+		},
+		{
+			name:     "optional field added",
+			old:      object(nil, map[string]*handler.Property{"name": str()}),
+			new:      object(nil, map[string]*handler.Property{"name": str(), "age": str()}),
+			request:  []string{"age: field added"},
+			response: []string{"age: field added"},
+		},
+		{
+			name:     "field removed",
+			old:      object(nil, map[string]*handler.Property{"name": str(), "age": str()}),
+			new:      object(nil, map[string]*handler.Property{"name": str()}),
+			request:  []string{"age: field removed"},
+			response: []string{"age: field removed (breaking)"},
+		},
+		{
+			name:     "field became required",
+			old:      object(nil, map[string]*handler.Property{"name": str()}),
+			new:      object([]string{"name"}, map[string]*handler.Property{"name": str()}),
+			request:  []string{"name: field became required (breaking)"},
+			response: []string{"name: field became required"},
+		},
+		{
+			name:     "field became optional",
+			old:      object([]string{"name"}, map[string]*handler.Property{"name": str()}),
+			new:      object(nil, map[string]*handler.Property{"name": str()}),
+			request:  []string{"name: field became optional"},
+			response: []string{"name: field became optional (breaking)"},
+		},
+		{
+			name:     "enum value added",
+			old:      &handler.Property{Type: constants.STRING_TYPE, Enum: []interface{}{"a", "b"}},
+			new:      &handler.Property{Type: constants.STRING_TYPE, Enum: []interface{}{"a", "b", "c"}},
+			request:  []string{": enum values [c] added"},
+			response: []string{": enum values [c] added (breaking)"},
+		},
+		{
+			name:     "enum value removed",
+			old:      &handler.Property{Type: constants.STRING_TYPE, Enum: []interface{}{"a", "b"}},
+			new:      &handler.Property{Type: constants.STRING_TYPE, Enum: []interface{}{"a"}},
+			request:  []string{": enum values [b] removed (breaking)"},
+			response: []string{": enum values [b] removed"},
+		},
+		{
+			name:     "maxLength lowered",
+			old:      &handler.Property{Type: constants.STRING_TYPE, MaxLength: 64},
+			new:      &handler.Property{Type: constants.STRING_TYPE, MaxLength: 32},
+			request:  []string{": maxLength changed from 64 to 32 (breaking)"},
+			response: []string{": maxLength changed from 64 to 32"},
+		},
+		{
+			name:     "maxLength raised",
+			old:      &handler.Property{Type: constants.STRING_TYPE, MaxLength: 32},
+			new:      &handler.Property{Type: constants.STRING_TYPE, MaxLength: 64},
+			request:  []string{": maxLength changed from 32 to 64"},
+			response: []string{": maxLength changed from 32 to 64 (breaking)"},
+		},
+		{
+			name:     "minimum added",
+			old:      &handler.Property{Type: constants.INTEGER_TYPE},
+			new:      &handler.Property{Type: constants.INTEGER_TYPE, Minimum: bound(1)},
+			request:  []string{": minimum 1 added (breaking)"},
+			response: []string{": minimum 1 added"},
+		},
+		{
+			name:     "maximum removed",
+			old:      &handler.Property{Type: constants.INTEGER_TYPE, Maximum: bound(10)},
+			new:      &handler.Property{Type: constants.INTEGER_TYPE},
+			request:  []string{": maximum 10 removed"},
+			response: []string{": maximum 10 removed (breaking)"},
+		},
+		{
+			name:     "integer widened to number",
+			old:      &handler.Property{Type: constants.INTEGER_TYPE},
+			new:      &handler.Property{Type: constants.NUMBER_TYPE},
+			request:  []string{": type changed from integer to number"},
+			response: []string{": type changed from integer to number (breaking)"},
+		},
+		{
+			name:     "type changed",
+			old:      &handler.Property{Type: constants.STRING_TYPE},
+			new:      &handler.Property{Type: constants.BOOLEAN_TYPE},
+			request:  []string{": type changed from string to boolean (breaking)"},
+			response: []string{": type changed from string to boolean (breaking)"},
+		},
+		{
+			name:     "became nullable",
+			old:      object(nil, map[string]*handler.Property{"name": str()}),
+			new:      object(nil, map[string]*handler.Property{"name": {Type: constants.STRING_TYPE, Nullable: true}}),
+			request:  []string{"name: became nullable"},
+			response: []string{"name: became nullable (breaking)"},
+		},
+		{
+			name:     "nested array item field",
+			old:      object(nil, map[string]*handler.Property{"lines": {Type: constants.ARRAY_TYPE, Items: object(nil, map[string]*handler.Property{"sku": str()})}}),
+			new:      object(nil, map[string]*handler.Property{"lines": {Type: constants.ARRAY_TYPE, Items: object([]string{"sku"}, map[string]*handler.Property{"sku": str()})}}),
+			request:  []string{"lines[].sku: field became required (breaking)"},
+			response: []string{"lines[].sku: field became required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compare(t, true, tt.old, tt.new); !reflect.DeepEqual(got, tt.request) {
+				t.Errorf("request: got %q, want %q", got, tt.request)
+			}
+			if got := compare(t, false, tt.old, tt.new); !reflect.DeepEqual(got, tt.response) {
+				t.Errorf("response: got %q, want %q", got, tt.response)
+			}
+		})
+	}
+}
+
+func TestCompareSchemaUnchanged(t *testing.T) {
+	schema := object([]string{"name"}, map[string]*handler.Property{
+		"name": {Type: constants.STRING_TYPE, MaxLength: 64, Enum: []interface{}{"a", "b"}},
+	})
+	for _, request := range []bool{true, false} {
+		if got := compare(t, request, schema, schema); len(got) != 0 {
+			t.Errorf("request %v: got %q, want no changes", request, got)
+		}
+	}
+}
@@ -34,6 +34,7 @@ const (
 type IFileFetcher interface {
 	InteractiveResolveRef(input input.IInputMethods, mode constants.InputMode, destBase string) (string, error)
 	FetchPathSchema(input input.IInputMethods) (string, string, error)
+	FetchModelFile(input input.IInputMethods) (string, error)
 }
 
 // FileFetcher handles file-specific fetching operations
@@ -113,26 +114,33 @@ func (ff *FileFetcher) InteractiveResolveRef(input input.IInputMethods, mode con
 			continue
 		}
 
-		// Build relative path
-		rel, err := filepath.Rel(destBase, selectedFile)
-		if err != nil {
-			return "", fmt.Errorf("[ERROR] relative path resolution failed")
-		}
-		rel = filepath.ToSlash(rel)
-		if !strings.HasSuffix(rel, YAML_EXT) && !strings.HasSuffix(rel, YML_EXT) {
-			// safety: ensure extension
-			rel += YAML_EXT
-		}
+		return BuildRef(destBase, selectedFile, pointer)
+	}
+}
 
-		// Ensure pointer starts with '#'
-		if pointer == "" {
-			pointer = JSON_POINTER_REF
-		} else if !strings.HasPrefix(pointer, JSON_POINTER_REF) {
-			pointer = JSON_POINTER_REF + pointer
-		}
+// BuildRef builds a $ref string pointing at pointer inside targetFile,
+// relative to destBase (the directory of the file that will contain the $ref).
+// pointer may be given with or without the leading '#'.
+func BuildRef(destBase, targetFile, pointer string) (string, error) {
+	// Build relative path
+	rel, err := filepath.Rel(destBase, targetFile)
+	if err != nil {
+		return "", fmt.Errorf("[ERROR] relative path resolution failed")
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasSuffix(rel, YAML_EXT) && !strings.HasSuffix(rel, YML_EXT) {
+		// safety: ensure extension
+		rel += YAML_EXT
+	}
 
-		return fmt.Sprintf("%s%s", rel, pointer), nil
+	// Ensure pointer starts with '#'
+	if pointer == "" {
+		pointer = JSON_POINTER_REF
+	} else if !strings.HasPrefix(pointer, JSON_POINTER_REF) {
+		pointer = JSON_POINTER_REF + pointer
 	}
+
+	return fmt.Sprintf("%s%s", rel, pointer), nil
 }
 
 // FetchPathSchema fetches a Path schema file interactively
@@ -191,6 +199,17 @@ func (ff *FileFetcher) FetchPathSchema(input input.IInputMethods) (string, strin
 	}
 }
 
+// FetchModelFile lets the user select a model file starting from SWAGEN_MODEL_PATH
+// Returns the path of the selected file
+func (ff *FileFetcher) FetchModelFile(input input.IInputMethods) (string, error) {
+	startPath := utils.GetEnv(utils.SWAGEN_MODEL_PATH, "")
+	if startPath == "" {
+		return "", errors.New("[ERROR] SWAGEN_MODEL_PATH is not set. Set it in environment or .env")
+	}
+
+	return ff.selectFileInteractive(input, startPath)
+}
+
 // decideStartPath asks for start directory based on mode and returns also the fileKind hint
 // fileKind: "model", "schema", or "auto"
 func (ff *FileFetcher) decideStartPath(input input.IInputMethods, mode constants.InputMode) (string, string, error) {
@@ -0,0 +1,67 @@
+package generator
+
+// References returns the types of the same module a type refers to directly
+func References(named *NamedType) []*NamedType {
+	refs := []*NamedType{}
+	var visit func(ref *TypeRef)
+	visit = func(ref *TypeRef) {
+		if ref == nil {
+			return
+		}
+		if ref.Named != nil {
+			if ref.Named.Module == named.Module {
+				refs = append(refs, ref.Named)
+			}
+			return
+		}
+		visit(ref.Items)
+	}
+	for _, field := range named.Fields {
+		visit(field.Type)
+	}
+	visit(named.Alias)
+	return refs
+}
+
+// DependencyOrder orders the types of a module so each type is defined before
+// the types using it; types of a cycle keep their original order
+func DependencyOrder(module *Module) []*NamedType {
+	order := []*NamedType{}
+	visited := map[*NamedType]bool{}
+
+	var visit func(named *NamedType)
+	visit = func(named *NamedType) {
+		if visited[named] {
+			return
+		}
+		visited[named] = true
+		for _, ref := range References(named) {
+			visit(ref)
+		}
+		order = append(order, named)
+	}
+
+	for _, named := range module.Types {
+		visit(named)
+	}
+	return order
+}
+
+// IsRecursive reports whether a type refers back to itself within its module
+func IsRecursive(named *NamedType) bool {
+	seen := map[*NamedType]bool{}
+	stack := References(named)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == named {
+			return true
+		}
+		if seen[current] {
+			continue
+		}
+		seen[current] = true
+		stack = append(stack, References(current)...)
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Daaaai0809/swagen-v2/generator"
+	"github.com/Daaaai0809/swagen-v2/generator/tmpl"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+const (
+	FORMAT_HTML     = "html"
+	FORMAT_MARKDOWN = "markdown"
+
+	HTML_FILE_NAME     = "index.html"
+	MARKDOWN_FILE_NAME = "index.md"
+
+	DEFAULT_TITLE = "API Reference"
+	// operations without tags are listed under this one
+	DEFAULT_TAG = "default"
+)
+
+var anchorPattern = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// Page is the data the documentation templates are executed against
+type Page struct {
+	Title string
+	Tags  []*Tag
+	Types []*Type
+}
+
+// Tag is a group of operations
+type Tag struct {
+	Name       string
+	Anchor     string
+	Operations []*tmpl.Operation
+}
+
+// Type is a named type of the index with the operations using it
+type Type struct {
+	*tmpl.Type
+	UsedBy []*tmpl.Operation
+}
+
+// Generator renders the operations and named types of a spec as a single
+// self-contained HTML or Markdown document
+type Generator struct {
+	Spec   *generator.Spec
+	Format string
+	Title  string
+}
+
+func NewGenerator(spec *generator.Spec, format, title string) *Generator {
+	if title == "" {
+		title = DEFAULT_TITLE
+	}
+	return &Generator{
+		Spec:   spec,
+		Format: format,
+		Title:  title,
+	}
+}
+
+func (g *Generator) Generate() ([]*generator.File, error) {
+	data, err := tmpl.NewData(g.Spec)
+	if err != nil {
+		return nil, err
+	}
+	page := NewPage(g.Spec.Project, data, g.Title)
+
+	var b bytes.Buffer
+	switch g.Format {
+	case FORMAT_HTML:
+		t := htmltemplate.Must(htmltemplate.New(HTML_FILE_NAME).Funcs(htmlFuncs).Parse(htmlTemplate))
+		if err := t.Execute(&b, page); err != nil {
+			return nil, fmt.Errorf("[ERROR] failed to render %s: %v", HTML_FILE_NAME, err)
+		}
+		return []*generator.File{{Path: HTML_FILE_NAME, Data: b.Bytes()}}, nil
+	case FORMAT_MARKDOWN:
+		t := template.Must(template.New(MARKDOWN_FILE_NAME).Funcs(markdownFuncs).Parse(markdownTemplate))
+		if err := t.Execute(&b, page); err != nil {
+			return nil, fmt.Errorf("[ERROR] failed to render %s: %v", MARKDOWN_FILE_NAME, err)
+		}
+		return []*generator.File{{Path: MARKDOWN_FILE_NAME, Data: b.Bytes()}}, nil
+	}
+	return nil, fmt.Errorf("[ERROR] unknown format %q: use %s or %s", g.Format, FORMAT_HTML, FORMAT_MARKDOWN)
+}
+
+// NewPage groups the operations by tag and links every named type to the
+// operations which use it, directly or through other types
+func NewPage(project *loader.Project, data *tmpl.Data, title string) *Page {
+	page := &Page{Title: title}
+
+	tags := map[string]*Tag{}
+	for _, op := range data.Operations {
+		names := op.Tags
+		if len(names) == 0 {
+			names = []string{DEFAULT_TAG}
+		}
+		for _, name := range names {
+			tag, ok := tags[name]
+			if !ok {
+				tag = &Tag{Name: name, Anchor: "tag-" + Anchor(name)}
+				tags[name] = tag
+				page.Tags = append(page.Tags, tag)
+			}
+			tag.Operations = append(tag.Operations, op)
+		}
+	}
+	sort.SliceStable(page.Tags, func(i, j int) bool {
+		return page.Tags[i].Name < page.Tags[j].Name
+	})
+
+	refs := project.References()
+	for _, t := range data.Types {
+		page.Types = append(page.Types, &Type{Type: t, UsedBy: usedBy(refs, data.Operations, t)})
+	}
+	return page
+}
+
+// usedBy returns the operations defining a type inline or reaching it through
+// $refs to the type, to an ancestor or to a part of it, like `refs who-uses`.
+// Field pointers such as user.yaml#/properties/email count as uses of the model.
+func usedBy(refs []loader.Reference, operations []*tmpl.Operation, t *tmpl.Type) []*tmpl.Operation {
+	at := loader.ParseTarget(t.Ref)
+	reachable := loader.Reachable(refs, at, false)
+
+	ops := []*tmpl.Operation{}
+	for _, op := range operations {
+		opAt := loader.Target{File: op.File, Pointer: "/" + strings.ToLower(op.Method)}
+		used := opAt.Overlaps(at)
+		for _, ref := range reachable {
+			if used {
+				break
+			}
+			used = opAt.Contains(ref.From)
+		}
+		if used {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// Anchor returns an HTML id for a name
+func Anchor(name string) string {
+	return anchorPattern.ReplaceAllString(name, "-")
+}
+
+// TypeAnchor returns the id of the index entry of a named type
+func TypeAnchor(t *tmpl.Type) string {
+	return "type-" + Anchor(t.Name)
+}
+
+// OperationAnchor returns the id of an operation
+func OperationAnchor(op *tmpl.Operation) string {
+	return "op-" + Anchor(op.Name)
+}
+
+// ObjectOf returns the object whose fields describe a type: the type itself,
+// the object an alias stands for, or the items of an array. Nil for primitives.
+func ObjectOf(ref *tmpl.TypeRef) *tmpl.Type {
+	seen := map[*tmpl.Type]bool{}
+	for ref != nil {
+		if ref.Named == nil {
+			ref = ref.Items
+			continue
+		}
+		if seen[ref.Named] {
+			return nil
+		}
+		seen[ref.Named] = true
+		switch ref.Named.Kind {
+		case generator.KIND_OBJECT:
+			return ref.Named
+		case generator.KIND_ALIAS:
+			ref = ref.Named.Alias
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// TypeLabel describes a type in words, e.g. "array of Order", calling link
+// for the name of every named type
+func TypeLabel(ref *tmpl.TypeRef, link func(t *tmpl.Type) string) string {
+	if ref == nil {
+		return ""
+	}
+	if ref.Named != nil {
+		return link(ref.Named)
+	}
+	if ref.Items != nil {
+		return "array of " + TypeLabel(ref.Items, link)
+	}
+	if ref.Type == "" {
+		return "any"
+	}
+	return ref.Type
+}
+
+// Format returns the format of a type or of its array items
+func Format(ref *tmpl.TypeRef) string {
+	for ref != nil {
+		if ref.Format != "" {
+			return ref.Format
+		}
+		ref = ref.Items
+	}
+	return ""
+}
+
+// Source returns the <file>#<pointer> location of a named type, without the
+// trailing # of file roots
+func Source(t *tmpl.Type) string {
+	return strings.TrimSuffix(t.Ref, "#")
+}
@@ -0,0 +1,172 @@
+package docs
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/generator/tmpl"
+)
+
+var htmlFuncs = template.FuncMap{
+	"typeAnchor": TypeAnchor,
+	"source":     Source,
+	"opAnchor":   OperationAnchor,
+	"object":     ObjectOf,
+	"format":     Format,
+	"typeLink": func(ref *tmpl.TypeRef) template.HTML {
+		return template.HTML(TypeLabel(ref, func(t *tmpl.Type) string {
+			name := template.HTMLEscapeString(t.Name)
+			return fmt.Sprintf(`<a href="#%s">%s</a>`, TypeAnchor(t), name)
+		}))
+	},
+	"lower": strings.ToLower,
+	"value": func(value interface{}) string {
+		return fmt.Sprint(value)
+	},
+}
+
+const htmlTemplate = `{{define "fields" -}}
+<table>
+<thead><tr><th>Field</th><th>Type</th><th>Format</th><th>Required</th><th>Nullable</th><th>Example</th><th>Description</th></tr></thead>
+<tbody>
+{{- range .Fields}}
+<tr>
+<td><code>{{.Name}}</code>{{if .ReadOnly}} <span class="badge">read only</span>{{end}}</td>
+<td>{{typeLink .Type}}</td>
+<td>{{format .Type}}</td>
+<td>{{if .Required}}yes{{end}}</td>
+<td>{{if .Type.Nullable}}yes{{end}}</td>
+<td>{{if .Example}}<code>{{.Example}}</code>{{end}}</td>
+<td>{{.Description}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+
+{{- define "schema" -}}
+{{if .}}<p>Schema: {{typeLink .}}</p>
+{{with object .}}{{template "fields" .}}{{end}}{{end}}
+{{- end -}}
+
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { margin: 0; font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; line-height: 1.5; }
+nav { position: fixed; top: 0; bottom: 0; left: 0; width: 280px; overflow-y: auto; padding: 16px; box-sizing: border-box; background: #f6f8fa; border-right: 1px solid #d0d7de; font-size: 14px; }
+nav ul { list-style: none; margin: 0; padding-left: 12px; }
+nav > ul { padding-left: 0; }
+main { margin-left: 280px; padding: 16px 32px; max-width: 1100px; }
+a { color: #0969da; text-decoration: none; }
+a:hover { text-decoration: underline; }
+h2 { border-bottom: 1px solid #d0d7de; padding-bottom: 4px; margin-top: 40px; }
+section.operation, section.type { border: 1px solid #d0d7de; border-radius: 6px; padding: 0 16px 8px; margin: 16px 0; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0; font-size: 14px; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+code { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 90%; }
+.method { display: inline-block; min-width: 56px; padding: 0 6px; border-radius: 4px; color: #fff; background: #6e7781; text-align: center; font-size: 13px; }
+.method.get { background: #1f883d; }
+.method.post { background: #0969da; }
+.method.put, .method.patch { background: #9a6700; }
+.method.delete { background: #cf222e; }
+.badge { font-size: 12px; color: #57606a; }
+.muted { color: #57606a; }
+</style>
+</head>
+<body>
+<nav>
+<strong>{{.Title}}</strong>
+<ul>
+{{- range .Tags}}
+<li><a href="#{{.Anchor}}">{{.Name}}</a>
+<ul>
+{{- range .Operations}}
+<li><a href="#{{opAnchor .}}"><code>{{.Method}} {{.Path}}</code></a></li>
+{{- end}}
+</ul>
+</li>
+{{- end}}
+<li><a href="#models">Models</a>
+<ul>
+{{- range .Types}}
+<li><a href="#{{typeAnchor .Type}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+</li>
+</ul>
+</nav>
+<main>
+<h1>{{.Title}}</h1>
+{{- range .Tags}}
+<h2 id="{{.Anchor}}">{{.Name}}</h2>
+{{- range .Operations}}
+<section class="operation" id="{{opAnchor .}}">
+<h3><span class="method {{lower .Method}}">{{.Method}}</span> <code>{{.Path}}</code></h3>
+{{- if .Summary}}
+<p><strong>{{.Summary}}</strong></p>
+{{- end}}
+{{- if .OperationID}}
+<p class="muted">operationId: <code>{{.OperationID}}</code></p>
+{{- end}}
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+{{- if .Parameters}}
+<h4>Parameters</h4>
+<table>
+<thead><tr><th>Name</th><th>In</th><th>Type</th><th>Format</th><th>Required</th><th>Nullable</th></tr></thead>
+<tbody>
+{{- range .Parameters}}
+<tr><td><code>{{.Name}}</code></td><td>{{.In}}</td><td>{{typeLink .Type}}</td><td>{{format .Type}}</td><td>{{if .Required}}yes{{end}}</td><td>{{if .Type.Nullable}}yes{{end}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- with .Body}}
+<h4>Request body</h4>
+<p><code>{{.MediaType}}</code>{{if .Required}} <span class="badge">required</span>{{end}}{{if .Description}} {{.Description}}{{end}}</p>
+{{template "schema" .Type}}
+{{- end}}
+<h4>Responses</h4>
+{{- range .Responses}}
+<h5><code>{{.Code}}</code>{{if .Description}} {{.Description}}{{end}}</h5>
+{{- if .MediaType}}
+<p><code>{{.MediaType}}</code></p>
+{{template "schema" .Type}}
+{{- end}}
+{{- end}}
+</section>
+{{- end}}
+{{- end}}
+<h2 id="models">Models</h2>
+{{- range .Types}}
+<section class="type" id="{{typeAnchor .Type}}">
+<h3>{{.Name}}</h3>
+<p class="muted">{{.Kind}} defined in <code>{{source .Type}}</code></p>
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+{{- if .Fields}}
+{{template "fields" .Type}}
+{{- end}}
+{{- if .Enum}}
+<p>Values: {{range $i, $value := .Enum}}{{if $i}}, {{end}}<code>{{value $value}}</code>{{end}}</p>
+{{- end}}
+{{- with .Alias}}
+<p>Alias of {{typeLink .}}</p>
+{{- end}}
+{{- if .UsedBy}}
+<p>Used by: {{range $i, $op := .UsedBy}}{{if $i}}, {{end}}<a href="#{{opAnchor $op}}"><code>{{$op.Method}} {{$op.Path}}</code></a>{{end}}</p>
+{{- end}}
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+`
@@ -0,0 +1,134 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Daaaai0809/swagen-v2/generator/tmpl"
+)
+
+var markdownFuncs = template.FuncMap{
+	"typeAnchor": TypeAnchor,
+	"source":     Source,
+	"opAnchor":   OperationAnchor,
+	"object":     ObjectOf,
+	"format":     Format,
+	"typeLink": func(ref *tmpl.TypeRef) string {
+		return TypeLabel(ref, func(t *tmpl.Type) string {
+			return fmt.Sprintf("[%s](#%s)", t.Name, TypeAnchor(t))
+		})
+	},
+	"cell": markdownCell,
+	"check": func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return ""
+	},
+	"enum": func(values []interface{}) string {
+		out := make([]string, 0, len(values))
+		for _, value := range values {
+			out = append(out, fmt.Sprintf("`%v`", value))
+		}
+		return strings.Join(out, ", ")
+	},
+}
+
+// markdownCell escapes text for a table cell, which must stay on one line
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+const markdownTemplate = `{{define "fields" -}}
+| Field | Type | Format | Required | Nullable | Example | Description |
+| --- | --- | --- | --- | --- | --- | --- |
+{{range .Fields -}}
+| ` + "`{{.Name}}`" + ` | {{typeLink .Type}} | {{format .Type}} | {{check .Required}} | {{check .Type.Nullable}} | {{cell .Example}} | {{cell .Description}}{{if .ReadOnly}} (read only){{end}} |
+{{end}}
+{{- end}}
+
+{{- define "schema" -}}
+{{if .}}Schema: {{typeLink .}}
+{{with object .}}
+{{template "fields" .}}{{end}}{{end}}
+{{- end -}}
+
+# {{.Title}}
+
+{{range .Tags -}}
+- [{{.Name}}](#{{.Anchor}})
+{{range .Operations}}  - [` + "`{{.Method}} {{.Path}}`" + `](#{{opAnchor .}}){{if .Summary}} {{.Summary}}{{end}}
+{{end}}
+{{- end -}}
+- [Models](#models)
+{{range .Tags}}
+<a id="{{.Anchor}}"></a>
+
+## {{.Name}}
+{{range .Operations}}
+<a id="{{opAnchor .}}"></a>
+
+### ` + "`{{.Method}} {{.Path}}`" + `
+{{if .Summary}}
+**{{.Summary}}**
+{{end}}
+{{- if .OperationID}}
+operationId: ` + "`{{.OperationID}}`" + `
+{{end}}
+{{- if .Description}}
+{{.Description}}
+{{end}}
+{{- if .Parameters}}
+#### Parameters
+
+| Name | In | Type | Format | Required | Nullable |
+| --- | --- | --- | --- | --- | --- |
+{{range .Parameters -}}
+| ` + "`{{.Name}}`" + ` | {{.In}} | {{typeLink .Type}} | {{format .Type}} | {{check .Required}} | {{check .Type.Nullable}} |
+{{end}}
+{{- end}}
+{{- with .Body}}
+#### Request body
+
+` + "`{{.MediaType}}`" + `{{if .Required}} (required){{end}}{{if .Description}}: {{.Description}}{{end}}
+
+{{template "schema" .Type}}
+{{- end}}
+#### Responses
+{{range .Responses}}
+##### {{.Code}}{{if .Description}} {{.Description}}{{end}}
+{{if .MediaType}}
+` + "`{{.MediaType}}`" + `
+
+{{template "schema" .Type}}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- end}}
+<a id="models"></a>
+
+## Models
+{{range .Types}}
+<a id="{{typeAnchor .Type}}"></a>
+
+### {{.Name}}
+
+{{.Kind}} defined in ` + "`{{source .Type}}`" + `
+{{if .Description}}
+{{.Description}}
+{{end}}
+{{- if .Fields}}
+{{template "fields" .Type}}
+{{- end}}
+{{- if .Enum}}
+Values: {{enum .Enum}}
+{{end}}
+{{- with .Alias}}
+Alias of {{typeLink .}}
+{{end}}
+{{- if .UsedBy}}
+Used by: {{range $i, $op := .UsedBy}}{{if $i}}, {{end}}[` + "`{{$op.Method}} {{$op.Path}}`" + `](#{{opAnchor $op}}){{end}}
+{{end}}
+{{- end}}`
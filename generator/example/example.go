@@ -0,0 +1,370 @@
+package example
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/generator"
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/handler/api"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+const (
+	DEFAULT_SEED = 1
+
+	// times a $ref may be followed inside its own expansion
+	MAX_RECURSION = 2
+
+	MIN_ITEMS = 1
+	MAX_ITEMS = 3
+)
+
+// baseTime is the earliest date-time synthesized, values are spread over the following year
+var baseTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var words = []string{
+	"alpha", "bravo", "charlie", "delta", "echo", "foxtrot",
+	"golf", "hotel", "india", "juliet", "kilo", "lima",
+}
+
+// errTooDeep is returned when a recursive schema cannot be expanded any further
+var errTooDeep = errors.New("recursion limit reached")
+
+// Synthesizer builds JSON instances valid against a schema. Explicit
+// `example` values are used as they are, everything else is drawn from a
+// random source seeded with Seed, so the same seed gives the same output.
+type Synthesizer struct {
+	Project *loader.Project
+	Seed    int64
+
+	rand      *rand.Rand
+	expanding map[loader.Target]int
+}
+
+func NewSynthesizer(project *loader.Project, seed int64) *Synthesizer {
+	return &Synthesizer{
+		Project: project,
+		Seed:    seed,
+	}
+}
+
+// Instance returns a value valid against schema, located at `at` so relative
+// $refs inside it resolve from its file. Every call starts from the seed.
+func (s *Synthesizer) Instance(at loader.Target, schema *handler.Property) (interface{}, error) {
+	s.rand = rand.New(rand.NewSource(s.Seed))
+	s.expanding = map[loader.Target]int{at: 1}
+
+	value, err := s.instance(at, schema, "")
+	if errors.Is(err, errTooDeep) {
+		return nil, fmt.Errorf("[ERROR] %s has no finite instance: every path through it is recursive and required", at)
+	}
+	return value, err
+}
+
+func (s *Synthesizer) instance(at loader.Target, schema *handler.Property, name string) (interface{}, error) {
+	if schema == nil {
+		return nil, nil
+	}
+
+	if schema.Ref != "" {
+		target, resolved, err := s.Project.ResolveRef(at.File, schema.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("%v (referenced from %s)", err, at)
+		}
+		if s.expanding[target] > MAX_RECURSION {
+			if schema.Nullable {
+				return nil, nil
+			}
+			return nil, errTooDeep
+		}
+		s.expanding[target]++
+		defer func() { s.expanding[target]-- }()
+		return s.instance(target, resolved, name)
+	}
+
+	if schema.Example != "" {
+		return ExampleValue(schema), nil
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[s.rand.Intn(len(schema.Enum))], nil
+	}
+
+	switch schemaType(schema) {
+	case constants.OBJECT_TYPE:
+		return s.object(at, schema)
+	case constants.ARRAY_TYPE:
+		return s.array(at, schema, name)
+	case constants.STRING_TYPE:
+		return s.str(schema, name), nil
+	case constants.INTEGER_TYPE:
+		return s.integer(schema), nil
+	case constants.NUMBER_TYPE:
+		return s.number(schema), nil
+	case constants.BOOLEAN_TYPE:
+		return s.rand.Intn(2) == 0, nil
+	}
+	return nil, nil
+}
+
+func (s *Synthesizer) object(at loader.Target, schema *handler.Property) (interface{}, error) {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	required := map[string]bool{}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	object := map[string]interface{}{}
+	for _, name := range names {
+		value, err := s.instance(at.Child(strings.TrimPrefix(fetcher.PROPERTIES_PATH, "/"), name), schema.Properties[name], name)
+		if errors.Is(err, errTooDeep) && !required[name] {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		object[name] = value
+	}
+	return object, nil
+}
+
+func (s *Synthesizer) array(at loader.Target, schema *handler.Property, name string) (interface{}, error) {
+	items := []interface{}{}
+	low, high := MIN_ITEMS, MAX_ITEMS
+	if schema.MinItems > 0 {
+		low = schema.MinItems
+		high = max(high, low)
+	}
+	if schema.MaxItems > 0 {
+		high = min(high, schema.MaxItems)
+		low = min(low, high)
+	}
+	count := low + s.rand.Intn(high-low+1)
+	for i := 0; i < count; i++ {
+		if schema.Items == nil {
+			// any item is valid, words read better than nulls
+			items = append(items, s.word())
+			continue
+		}
+		item, err := s.instance(at.Child(fetcher.ITEMS_OPTION), schema.Items, name)
+		if errors.Is(err, errTooDeep) {
+			// an empty array ends the recursion
+			return []interface{}{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (s *Synthesizer) word() string {
+	return words[s.rand.Intn(len(words))]
+}
+
+func (s *Synthesizer) str(schema *handler.Property, name string) string {
+	var value string
+	switch schema.Format {
+	case constants.FORMAT_UUID:
+		b := make([]byte, 16)
+		s.rand.Read(b)
+		b[6] = b[6]&0x0f | 0x40 // version 4
+		b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+		value = fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	case constants.FORMAT_EMAIL:
+		value = s.word() + "." + s.word() + "@example.com"
+	case constants.FORMAT_DATE_TIME:
+		value = s.dateTime().Format(time.RFC3339)
+	case constants.FORMAT_DATE:
+		value = s.dateTime().Format(time.DateOnly)
+	case constants.FORMAT_URI, constants.FORMAT_URI_REF:
+		value = "https://example.com/" + s.word()
+	case constants.FORMAT_HOSTNAME:
+		value = s.word() + ".example.com"
+	case constants.FORMAT_IPV4:
+		// TEST-NET-1, reserved for documentation
+		value = fmt.Sprintf("192.0.2.%d", s.rand.Intn(254)+1)
+	case constants.FORMAT_IPV6:
+		value = fmt.Sprintf("2001:db8::%x", s.rand.Intn(0xffff)+1)
+	case constants.FORMAT_BYTE:
+		value = base64.StdEncoding.EncodeToString([]byte(s.word()))
+	case constants.FORMAT_PASSWORD:
+		value = "P@ss-" + s.word() + "-" + strconv.Itoa(s.rand.Intn(1000))
+	default:
+		value = s.word()
+		if name != "" {
+			value = name + "-" + value
+		}
+	}
+
+	if schema.Format == "" || schema.Format == constants.FORMAT_NONE {
+		for len([]rune(value)) < schema.MinLength {
+			value += "-" + s.word()
+		}
+	}
+	if schema.MaxLength > 0 {
+		if runes := []rune(value); len(runes) > schema.MaxLength {
+			value = string(runes[:schema.MaxLength])
+		}
+	}
+	return value
+}
+
+// integer returns an integer within minimum and maximum, 1 to 1000 without them
+func (s *Synthesizer) integer(schema *handler.Property) int {
+	low, high := 1, 1000
+	if schema.Minimum != nil {
+		low = int(math.Ceil(*schema.Minimum))
+		high = low + 999
+	}
+	if schema.Maximum != nil {
+		high = int(math.Floor(*schema.Maximum))
+		if schema.Minimum == nil && high < low {
+			low = high - 999
+		}
+	}
+	if high < low {
+		return low
+	}
+	return low + s.rand.Intn(high-low+1)
+}
+
+// number returns a number with two decimals within minimum and maximum, 0 to 1000 without them
+func (s *Synthesizer) number(schema *handler.Property) float64 {
+	low, high := 0.0, 1000.0
+	if schema.Minimum != nil {
+		low = *schema.Minimum
+		high = low + 1000
+	}
+	if schema.Maximum != nil {
+		high = *schema.Maximum
+		if schema.Minimum == nil && high < low {
+			low = high - 1000
+		}
+	}
+	if high < low {
+		return low
+	}
+	value := math.Round((low+s.rand.Float64()*(high-low))*100) / 100
+	// rounding may step outside the bounds
+	return math.Min(math.Max(value, low), high)
+}
+
+func (s *Synthesizer) dateTime() time.Time {
+	return baseTime.Add(time.Duration(s.rand.Int63n(int64(365*24*time.Hour/time.Second))) * time.Second)
+}
+
+// schemaType is the type of a schema, objects may leave it out when they have properties
+func schemaType(schema *handler.Property) string {
+	if schema.Type == "" && len(schema.Properties) > 0 {
+		return constants.OBJECT_TYPE
+	}
+	return schema.Type
+}
+
+// ExampleValue returns the `example` of a schema as a value of the schema's
+// type; examples of objects and arrays are parsed as JSON
+func ExampleValue(schema *handler.Property) interface{} {
+	example := schema.Example
+	switch schemaType(schema) {
+	case constants.INTEGER_TYPE:
+		if v, err := strconv.ParseInt(example, 10, 64); err == nil {
+			return v
+		}
+	case constants.NUMBER_TYPE:
+		if v, err := strconv.ParseFloat(example, 64); err == nil {
+			return v
+		}
+	case constants.BOOLEAN_TYPE:
+		if v, err := strconv.ParseBool(example); err == nil {
+			return v
+		}
+	case constants.OBJECT_TYPE, constants.ARRAY_TYPE:
+		var v interface{}
+		if err := json.Unmarshal([]byte(example), &v); err == nil {
+			return v
+		}
+	}
+	return example
+}
+
+// Operation returns an example of every parameter, the request body and every
+// response of an operation, each in its preferred media type:
+// {"parameters": {<name>: ...}, "requestBody": ..., "responses": {<code>: ...}}.
+// A request body or response without content, e.g. a 204, is left out.
+func (s *Synthesizer) Operation(op *loader.Operation) (map[string]interface{}, error) {
+	at := loader.Target{File: op.Document.File, Pointer: op.Pointer()}
+	out := map[string]interface{}{}
+
+	if len(op.API.Parameters) > 0 {
+		params := map[string]interface{}{}
+		for i, param := range op.API.Parameters {
+			if param == nil {
+				continue
+			}
+			value, err := s.Instance(at.Child("parameters", strconv.Itoa(i), "schema"), loader.ParameterSchema(param))
+			if err != nil {
+				return nil, err
+			}
+			params[param.Name] = value
+		}
+		out["parameters"] = params
+	}
+
+	if body := op.API.RequestBody; body != nil {
+		value, ok, err := s.content(at.Child("requestBody"), body.Content)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out["requestBody"] = value
+		}
+	}
+
+	if len(op.API.Responses) > 0 {
+		responses := map[string]interface{}{}
+		for code, response := range op.API.Responses {
+			if response == nil {
+				continue
+			}
+			value, ok, err := s.content(at.Child("responses", code), response.Content)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				responses[code] = value
+			}
+		}
+		if len(responses) > 0 {
+			out["responses"] = responses
+		}
+	}
+	return out, nil
+}
+
+// content returns an example of the preferred media type of a content map,
+// false when there is none
+func (s *Synthesizer) content(at loader.Target, content map[string]*api.MediaType) (interface{}, bool, error) {
+	mediaType := generator.PreferredMediaType(content)
+	if mediaType == "" || content[mediaType] == nil || content[mediaType].Schema == nil {
+		return nil, false, nil
+	}
+	value, err := s.Instance(at.Child("content", mediaType, "schema"), content[mediaType].Schema)
+	return value, err == nil, err
+}
@@ -0,0 +1,390 @@
+package golang
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/generator"
+)
+
+const (
+	CLIENT_FILE_NAME = "client" + FILE_EXT
+
+	// fields of the result structs which are not responses
+	STATUS_CODE_FIELD = "StatusCode"
+	HEADER_FIELD      = "Header"
+)
+
+// clientIdentifiers are the exported names every client file defines
+var clientIdentifiers = []string{"Client", "NewClient", "UnexpectedStatusError"}
+
+// ParamsName returns the name of the struct holding the parameters of an operation
+func ParamsName(op *generator.Operation) string {
+	return OperationName(op) + "Params"
+}
+
+// ResultName returns the name of the struct holding the decoded response of an operation
+func ResultName(op *generator.Operation) string {
+	return OperationName(op) + "Result"
+}
+
+// ResultField returns the field of the result struct holding the body of a status code
+func ResultField(code string) string {
+	if code == generator.DEFAULT_RESPONSE_CODE {
+		return "Default"
+	}
+	return "Status" + strings.ToUpper(code)
+}
+
+// GenerateClient returns the Go types of the spec and a client file with a
+// method per operation which encodes its parameters and body and decodes
+// every documented status code into its typed response
+func (g *Generator) GenerateClient() ([]*generator.File, error) {
+	operations, err := g.Spec.Operations()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.generateOperations(operations, CLIENT_FILE_NAME, clientIdentifiers, func(op *generator.Operation) []string {
+		names := []string{ResultName(op)}
+		if len(op.Parameters) > 0 {
+			names = append(names, ParamsName(op))
+		}
+		return names
+	}, g.generateClient)
+}
+
+func (g *Generator) generateClient(operations []*generator.Operation) ([]byte, error) {
+	imports := map[string]bool{
+		"bytes":           true,
+		"context":         true,
+		"encoding/base64": true,
+		"encoding/json":   true,
+		"fmt":             true,
+		"io":              true,
+		"net/http":        true,
+		"net/url":         true,
+		"reflect":         true,
+		"strconv":         true,
+		"strings":         true,
+		"time":            true,
+	}
+
+	var body bytes.Buffer
+	body.WriteString(clientRuntime)
+	for _, op := range operations {
+		g.writeParams(&body, op, imports)
+		g.writeResult(&body, op, imports)
+		if err := g.writeClientMethod(&body, op, imports); err != nil {
+			return nil, err
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString(HEADER + "\n\n")
+	fmt.Fprintf(&b, "package %s\n", g.Package)
+	writeImports(&b, imports)
+	b.Write(body.Bytes())
+
+	return formatSource(b.Bytes(), CLIENT_FILE_NAME)
+}
+
+func (g *Generator) writeParams(b *bytes.Buffer, op *generator.Operation, imports map[string]bool) {
+	if len(op.Parameters) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "\n// %s are the parameters of %s\ntype %s struct {\n", ParamsName(op), OperationName(op), ParamsName(op))
+	fields := inputFields(op)
+	for i, param := range op.Parameters {
+		fmt.Fprintf(b, "\t// %s is the %s parameter %s\n", fields[i], param.In, param.Name)
+		fmt.Fprintf(b, "\t%s %s\n", fields[i], g.ParamType(param.Type, param.Required, imports))
+	}
+	b.WriteString("}\n")
+}
+
+func (g *Generator) writeResult(b *bytes.Buffer, op *generator.Operation, imports map[string]bool) {
+	fmt.Fprintf(b, "\n// %s is the decoded response of %s. Only the field of the\n", ResultName(op), OperationName(op))
+	b.WriteString("// status code which was received is set.\n")
+	fmt.Fprintf(b, "type %s struct {\n", ResultName(op))
+	fmt.Fprintf(b, "\t%s int\n", STATUS_CODE_FIELD)
+	fmt.Fprintf(b, "\t%s http.Header\n", HEADER_FIELD)
+	for _, response := range op.Responses {
+		if response.Type == nil {
+			continue
+		}
+		if response.Description != "" {
+			writeComment(b, "\t", ResultField(response.Code)+" "+response.Description)
+		}
+		fmt.Fprintf(b, "\t%s %s\n", ResultField(response.Code), g.BodyType(response.MediaType, response.Type, false, imports))
+	}
+	b.WriteString("}\n")
+}
+
+func (g *Generator) writeClientMethod(b *bytes.Buffer, op *generator.Operation, imports map[string]bool) error {
+	args := []string{"ctx context.Context"}
+	if len(op.Parameters) > 0 {
+		args = append(args, "params *"+ParamsName(op))
+	}
+	if op.Body != nil {
+		args = append(args, "body "+g.BodyType(op.Body.MediaType, op.Body.Type, op.Body.Required, imports))
+	}
+
+	b.WriteString("\n")
+	description := op.Description
+	if description == "" {
+		description = "calls " + strings.ToUpper(op.Method) + " " + op.Path
+	}
+	writeComment(b, "", OperationName(op)+" "+description)
+	fmt.Fprintf(b, "func (c *Client) %s(%s) (*%s, error) {\n", OperationName(op), strings.Join(args, ", "), ResultName(op))
+	fmt.Fprintf(b, "\treq := newClientRequest(%q, %q)\n", strings.ToUpper(op.Method), op.Path)
+
+	if len(op.Parameters) > 0 {
+		fmt.Fprintf(b, "\tif params == nil {\n\t\tparams = &%s{}\n\t}\n", ParamsName(op))
+		fields := inputFields(op)
+		for i, param := range op.Parameters {
+			var setter string
+			switch param.In {
+			case constants.PARAM_IN_PATH:
+				setter = "pathParam"
+			case constants.PARAM_IN_QUERY:
+				setter = "queryParam"
+			case constants.PARAM_IN_HEADER:
+				setter = "headerParam"
+			case constants.PARAM_IN_COOKIE:
+				setter = "cookieParam"
+			default:
+				return fmt.Errorf("[ERROR] parameter %s of %s %s is in unknown location %q", param.Name, op.Method, op.Path, param.In)
+			}
+			fmt.Fprintf(b, "\treq.%s(%q, params.%s)\n", setter, param.Name, fields[i])
+		}
+	}
+	if op.Body != nil {
+		fmt.Fprintf(b, "\tif err := req.setBody(%q, body); err != nil {\n\t\treturn nil, err\n\t}\n", op.Body.MediaType)
+	}
+
+	b.WriteString("\tres, data, err := c.do(ctx, req)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\n")
+	fmt.Fprintf(b, "\tresult := &%s{%s: res.StatusCode, %s: res.Header}\n", ResultName(op), STATUS_CODE_FIELD, HEADER_FIELD)
+	b.WriteString("\tswitch {\n")
+	hasDefault := false
+	for _, response := range op.Responses {
+		switch {
+		case response.Code == generator.DEFAULT_RESPONSE_CODE:
+			hasDefault = true
+			b.WriteString("\tdefault:\n")
+		case generator.IsFixedStatus(response.Code):
+			fmt.Fprintf(b, "\tcase res.StatusCode == %s:\n", response.Code)
+		default:
+			class, ok := generator.StatusClass(response.Code)
+			if !ok {
+				fmt.Printf("[WARN] response %s of %s %s is not a status code and is skipped\n", response.Code, op.Method, op.Path)
+				continue
+			}
+			fmt.Fprintf(b, "\tcase res.StatusCode/100 == %d:\n", class)
+		}
+		if response.Type != nil {
+			fmt.Fprintf(b, "\t\terr = decodeResponse(%q, data, &result.%s)\n", response.MediaType, ResultField(response.Code))
+		}
+	}
+	if !hasDefault {
+		fmt.Fprintf(b, "\tdefault:\n\t\treturn nil, &UnexpectedStatusError{Operation: %q, StatusCode: res.StatusCode, Header: res.Header, Body: data}\n", OperationName(op))
+	}
+	b.WriteString("\t}\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\treturn result, nil\n}\n")
+	return nil
+}
+
+// clientRuntime encodes requests and decodes responses for the generated methods
+const clientRuntime = `
+// Client calls the operations of the API over HTTP
+type Client struct {
+	// BaseURL is prepended to the path of every operation, e.g. https://api.example.com/v1
+	BaseURL string
+	// HTTPClient sends the requests, http.DefaultClient when nil
+	HTTPClient *http.Client
+	// Header is added to every request, e.g. for authorization
+	Header http.Header
+}
+
+// NewClient returns a client calling the API at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// UnexpectedStatusError is returned for a status code the operation does not document
+type UnexpectedStatusError struct {
+	Operation  string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *UnexpectedStatusError) Error() string {
+	return fmt.Sprintf("%s: unexpected status %d", e.Operation, e.StatusCode)
+}
+
+type clientRequest struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	cookies     []*http.Cookie
+	contentType string
+	body        io.Reader
+}
+
+func newClientRequest(method, path string) *clientRequest {
+	return &clientRequest{method: method, path: path, query: url.Values{}, header: http.Header{}}
+}
+
+func (r *clientRequest) pathParam(name string, value any) {
+	r.path = strings.ReplaceAll(r.path, "{"+name+"}", url.PathEscape(strings.Join(encodeParam(value), ",")))
+}
+
+func (r *clientRequest) queryParam(name string, value any) {
+	for _, v := range encodeParam(value) {
+		r.query.Add(name, v)
+	}
+}
+
+func (r *clientRequest) headerParam(name string, value any) {
+	if values := encodeParam(value); len(values) > 0 {
+		r.header.Set(name, strings.Join(values, ","))
+	}
+}
+
+func (r *clientRequest) cookieParam(name string, value any) {
+	if values := encodeParam(value); len(values) > 0 {
+		r.cookies = append(r.cookies, &http.Cookie{Name: name, Value: strings.Join(values, ",")})
+	}
+}
+
+// setBody encodes the body as JSON for JSON media types and sends []byte as is
+func (r *clientRequest) setBody(mediaType string, body any) error {
+	if isNil(reflect.ValueOf(body)) {
+		return nil
+	}
+	r.contentType = mediaType
+	if raw, ok := body.([]byte); ok && !isJSONMediaType(mediaType) {
+		r.body = bytes.NewReader(raw)
+		return nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	r.body = bytes.NewReader(data)
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, r *clientRequest) (*http.Response, []byte, error) {
+	target := c.BaseURL + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, target, r.body)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name, values := range c.Header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	for name, values := range r.header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	for _, cookie := range r.cookies {
+		req.AddCookie(cookie)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, data, nil
+}
+
+// decodeResponse decodes a response body into dst, content which is not JSON is read into a *[]byte
+func decodeResponse(mediaType string, data []byte, dst any) error {
+	if raw, ok := dst.(*[]byte); ok && !isJSONMediaType(mediaType) {
+		*raw = data
+		return nil
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, dst)
+}
+
+func isNil(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// encodeParam returns the text of every value of a parameter; unset optional
+// parameters have none and arrays have one per item
+func encodeParam(value any) []string {
+	v := reflect.ValueOf(value)
+	for v.IsValid() && v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if isNil(v) {
+		return nil
+	}
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		values := []string{}
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, encodeParam(v.Index(i).Interface())...)
+		}
+		return values
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		return []string{t.Format(time.RFC3339)}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return []string{v.String()}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return []string{strconv.FormatInt(v.Int(), 10)}
+	case reflect.Float32, reflect.Float64:
+		return []string{strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())}
+	case reflect.Bool:
+		return []string{strconv.FormatBool(v.Bool())}
+	case reflect.Slice:
+		return []string{base64.StdEncoding.EncodeToString(v.Bytes())}
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return []string{fmt.Sprint(v.Interface())}
+	}
+	return []string{string(data)}
+}
+`
@@ -0,0 +1,346 @@
+package golang
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/generator"
+)
+
+const (
+	FILE_EXT        = ".gen.go"
+	HEADER          = "// Code generated by swagen-v2. DO NOT EDIT."
+	DEFAULT_PACKAGE = "api"
+)
+
+// initialisms are written in upper case in Go identifiers
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "RPC": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "URI": true,
+	"URL": true, "UTF8": true, "UUID": true, "XML": true,
+}
+
+// Generator emits Go types with json tags for every named type of a spec
+type Generator struct {
+	Spec    *generator.Spec
+	Package string
+
+	names map[*generator.NamedType]string
+}
+
+func NewGenerator(spec *generator.Spec, packageName string) *Generator {
+	return &Generator{
+		Spec:    spec,
+		Package: packageName,
+		names:   make(map[*generator.NamedType]string),
+	}
+}
+
+// PackageName derives a package name from the output directory, DEFAULT_PACKAGE
+// when the directory name is no valid package name, e.g. "go" or "type"
+func PackageName(outDir string) string {
+	name := strings.ToLower(filepath.Base(filepath.Clean(outDir)))
+	name = strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return -1
+	}, name)
+	if name == "" || unicode.IsDigit(rune(name[0])) || token.IsKeyword(name) {
+		return DEFAULT_PACKAGE
+	}
+	return name
+}
+
+// CheckPackageName rejects package names given on the command line which
+// cannot be compiled
+func CheckPackageName(name string) error {
+	if !token.IsIdentifier(name) || token.IsKeyword(name) {
+		return fmt.Errorf("[ERROR] %q is not a valid Go package name", name)
+	}
+	return nil
+}
+
+// Identifier joins words into an exported Go identifier: "user_id" -> "UserID"
+func Identifier(parts ...string) string {
+	var b strings.Builder
+	for _, part := range parts {
+		for _, word := range generator.Words(part) {
+			if upper := strings.ToUpper(word); initialisms[upper] {
+				b.WriteString(upper)
+				continue
+			}
+			runes := []rune(word)
+			b.WriteRune(unicode.ToUpper(runes[0]))
+			b.WriteString(string(runes[1:]))
+		}
+	}
+
+	name := b.String()
+	if name == "" {
+		return ""
+	}
+	if unicode.IsDigit(rune(name[0])) {
+		return "X" + name
+	}
+	return name
+}
+
+// FileName returns the file a module is written to, e.g. model/user.yaml -> user.gen.go
+func (g *Generator) FileName(module *generator.Module) string {
+	return generator.Snake(g.Spec.RelativeModulePath(module)) + FILE_EXT
+}
+
+// TypeName returns the Go name of a named type
+func (g *Generator) TypeName(named *generator.NamedType) string {
+	if name, ok := g.names[named]; ok {
+		return name
+	}
+	name := Identifier(named.Parts...)
+	g.names[named] = name
+	return name
+}
+
+// TypeExpr returns the Go type expression of a type
+func (g *Generator) TypeExpr(ref *generator.TypeRef, imports map[string]bool) string {
+	if ref.Named != nil {
+		return g.TypeName(ref.Named)
+	}
+
+	switch ref.Type {
+	case constants.STRING_TYPE:
+		switch ref.Format {
+		case constants.FORMAT_DATE_TIME:
+			imports["time"] = true
+			return "time.Time"
+		case constants.FORMAT_BYTE, constants.FORMAT_BINARY:
+			return "[]byte"
+		}
+		return "string"
+	case constants.INTEGER_TYPE:
+		switch ref.Format {
+		case constants.FORMAT_INT32:
+			return "int32"
+		case constants.FORMAT_INT64:
+			return "int64"
+		}
+		return "int"
+	case constants.NUMBER_TYPE:
+		if ref.Format == constants.FORMAT_FLOAT {
+			return "float32"
+		}
+		return "float64"
+	case constants.BOOLEAN_TYPE:
+		return "bool"
+	case constants.ARRAY_TYPE:
+		if ref.Items == nil {
+			return "[]any"
+		}
+		elem := g.TypeExpr(ref.Items, imports)
+		if ref.Items.Nullable && !IsNilable(ref.Items) {
+			elem = "*" + elem
+		}
+		return "[]" + elem
+	case constants.OBJECT_TYPE:
+		return "map[string]any"
+	}
+	return "any"
+}
+
+// IsNilable reports whether the Go type of ref can already hold nil
+func IsNilable(ref *generator.TypeRef) bool {
+	if ref.Named != nil {
+		if ref.Named.Kind == generator.KIND_ALIAS {
+			return IsNilable(ref.Named.Alias)
+		}
+		return false
+	}
+
+	switch ref.Type {
+	case constants.ARRAY_TYPE, constants.OBJECT_TYPE, "":
+		return true
+	case constants.STRING_TYPE:
+		return ref.Format == constants.FORMAT_BYTE || ref.Format == constants.FORMAT_BINARY
+	}
+	return false
+}
+
+// FieldType returns the Go type of a struct field and whether it is omitted when empty.
+// Optional and nullable fields become pointers unless their type can hold nil already.
+func (g *Generator) FieldType(field *generator.Field, imports map[string]bool) (string, bool) {
+	expr := g.TypeExpr(field.Type, imports)
+	if !IsNilable(field.Type) && (field.Type.Nullable || !field.Required) {
+		expr = "*" + expr
+	}
+	return expr, !field.Required
+}
+
+// Generate returns one formatted Go file per module
+func (g *Generator) Generate() ([]*generator.File, error) {
+	if err := g.checkNames(); err != nil {
+		return nil, err
+	}
+
+	files := []*generator.File{}
+	used := map[string]bool{}
+	for _, module := range g.Spec.Modules {
+		name := g.FileName(module)
+		if used[name] {
+			name = strings.TrimSuffix(name, FILE_EXT) + "_" + module.Document.Kind + FILE_EXT
+		}
+		used[name] = true
+
+		data, err := g.generateModule(module)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &generator.File{Path: name, Data: data})
+	}
+	return files, nil
+}
+
+func (g *Generator) checkNames() error {
+	seen := map[string]*generator.NamedType{}
+	for _, named := range g.Spec.Types() {
+		name := g.TypeName(named)
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("[ERROR] Go type name %s is used by both %s and %s", name, other.Location, named.Location)
+		}
+		seen[name] = named
+	}
+	return nil
+}
+
+func (g *Generator) generateModule(module *generator.Module) ([]byte, error) {
+	imports := map[string]bool{}
+	var body bytes.Buffer
+	for _, named := range module.Types {
+		body.WriteString("\n")
+		g.writeType(&body, named, imports)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(HEADER + "\n")
+	fmt.Fprintf(&b, "// Source: %s\n\n", filepath.ToSlash(module.Document.File))
+	fmt.Fprintf(&b, "package %s\n", g.Package)
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		b.WriteString("\nimport (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&b, "\t%q\n", path)
+		}
+		b.WriteString(")\n")
+	}
+	b.Write(body.Bytes())
+
+	return formatSource(b.Bytes(), module.Document.File)
+}
+
+func formatSource(src []byte, source string) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] generated code for %s does not compile: %v", source, err)
+	}
+	return formatted, nil
+}
+
+func writeComment(b *bytes.Buffer, indent, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Fprintf(b, "%s// %s\n", indent, strings.TrimRight(line, " \t\r"))
+	}
+}
+
+func (g *Generator) writeType(b *bytes.Buffer, named *generator.NamedType, imports map[string]bool) {
+	name := g.TypeName(named)
+	if named.Description != "" {
+		writeComment(b, "", named.Description)
+	}
+
+	switch named.Kind {
+	case generator.KIND_OBJECT:
+		fmt.Fprintf(b, "type %s struct {\n", name)
+		fieldNames := map[string]bool{}
+		for _, field := range named.Fields {
+			if field.Description != "" {
+				writeComment(b, "\t", field.Description)
+			}
+			expr, omitEmpty := g.FieldType(field, imports)
+			tag := field.Name
+			if omitEmpty {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(b, "\t%s %s `json:%q`\n", uniqueName(Identifier(field.Name), "Field", fieldNames), expr, tag)
+		}
+		b.WriteString("}\n")
+	case generator.KIND_ENUM:
+		base := g.TypeExpr(&generator.TypeRef{Type: named.EnumType, Format: named.Schema.Format}, imports)
+		fmt.Fprintf(b, "type %s %s\n\n", name, base)
+		b.WriteString("const (\n")
+		constNames := map[string]bool{}
+		for _, value := range named.Enum {
+			constName := uniqueName(name+enumValueName(value), name+"Value", constNames)
+			fmt.Fprintf(b, "\t%s %s = %s\n", constName, name, literal(value, named.EnumType))
+		}
+		b.WriteString(")\n")
+	case generator.KIND_ALIAS:
+		fmt.Fprintf(b, "type %s = %s\n", name, g.TypeExpr(named.Alias, imports))
+	}
+}
+
+func enumValueName(value interface{}) string {
+	text := fmt.Sprint(value)
+	unsigned := strings.TrimPrefix(text, "-")
+	name := Identifier(unsigned)
+	if unsigned != "" && unicode.IsDigit(rune(unsigned[0])) {
+		name = strings.TrimPrefix(name, "X")
+	}
+	if name == "" {
+		return "Empty"
+	}
+	if unsigned != text {
+		name = "Minus" + name
+	}
+	return name
+}
+
+func uniqueName(name, fallback string, used map[string]bool) string {
+	if name == "" {
+		name = fallback
+	}
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
+
+func literal(value interface{}, enumType string) string {
+	switch enumType {
+	case constants.INTEGER_TYPE:
+		switch v := value.(type) {
+		case float64:
+			return strconv.FormatInt(int64(v), 10)
+		case float32:
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return fmt.Sprint(value)
+	case constants.NUMBER_TYPE, constants.BOOLEAN_TYPE:
+		return fmt.Sprint(value)
+	}
+	return strconv.Quote(fmt.Sprint(value))
+}
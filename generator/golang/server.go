@@ -0,0 +1,557 @@
+package golang
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/generator"
+)
+
+const (
+	SERVER_FILE_NAME  = "server" + FILE_EXT
+	RUNTIME_FILE_NAME = "swagen" + FILE_EXT
+
+	// fields of the input structs which are not parameters
+	BODY_FIELD         = "Body"
+	HTTP_REQUEST_FIELD = "HTTPRequest"
+)
+
+// wildcardPattern is a path segment net/http's ServeMux accepts as a wildcard
+var wildcardPattern = regexp.MustCompile(`^\{[A-Za-z_][A-Za-z0-9_]*\}$`)
+
+// serverIdentifiers are the exported names every server file defines
+var serverIdentifiers = []string{"Server", "Response", "RequestError", "ErrorHandler", "DefaultErrorHandler", "RegisterRoutes"}
+
+// OperationName returns the Go name of an operation, e.g. "getUser" -> "GetUser"
+func OperationName(op *generator.Operation) string {
+	return Identifier(op.Parts...)
+}
+
+// InputName returns the name of the struct holding the decoded request of an operation
+func InputName(op *generator.Operation) string {
+	return OperationName(op) + "Input"
+}
+
+// ResponseName returns the name of the helper building the response of a status code
+func ResponseName(op *generator.Operation, code string) string {
+	switch {
+	case code == generator.DEFAULT_RESPONSE_CODE:
+		return OperationName(op) + "Default"
+	case generator.IsFixedStatus(code):
+		return OperationName(op) + code
+	}
+	return OperationName(op) + strings.ToUpper(code)
+}
+
+// Pattern returns the net/http ServeMux pattern of an operation, e.g. "GET /users/{id}"
+func Pattern(op *generator.Operation) (string, error) {
+	path := op.Path
+	for _, segment := range strings.Split(path, "/") {
+		if strings.ContainsAny(segment, "{}") && !wildcardPattern.MatchString(segment) {
+			return "", fmt.Errorf("[ERROR] path parameter %s of %s is not a valid net/http wildcard", segment, op.Path)
+		}
+	}
+	if path == "/" {
+		// only the root itself, not every path
+		path = "/{$}"
+	}
+	return strings.ToUpper(op.Method) + " " + path, nil
+}
+
+// ParamType returns the Go type of a parameter or body field. Optional ones
+// become pointers unless their type can hold nil already.
+func (g *Generator) ParamType(ref *generator.TypeRef, required bool, imports map[string]bool) string {
+	expr := g.TypeExpr(ref, imports)
+	if !IsNilable(ref) && (ref.Nullable || !required) {
+		expr = "*" + expr
+	}
+	return expr
+}
+
+// BodyType returns the Go type of a request or response body; content which
+// is not JSON is passed as raw bytes
+func (g *Generator) BodyType(mediaType string, ref *generator.TypeRef, required bool, imports map[string]bool) string {
+	if !generator.IsJSONMediaType(mediaType) {
+		return "[]byte"
+	}
+	return g.ParamType(ref, required, imports)
+}
+
+// inputFields returns the struct field name of every parameter of an operation
+func inputFields(op *generator.Operation) []string {
+	used := map[string]bool{BODY_FIELD: true, HTTP_REQUEST_FIELD: true}
+	fields := make([]string, 0, len(op.Parameters))
+	for _, param := range op.Parameters {
+		fields = append(fields, uniqueName(Identifier(param.Name), "Param", used))
+	}
+	return fields
+}
+
+// GenerateServer returns the Go types of the spec and a server file with an
+// interface of every operation, their input structs and response helpers,
+// and the registration of the routes on a net/http ServeMux
+func (g *Generator) GenerateServer() ([]*generator.File, error) {
+	operations, err := g.Spec.Operations()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.generateOperations(operations, SERVER_FILE_NAME, serverIdentifiers, func(op *generator.Operation) []string {
+		names := []string{InputName(op)}
+		for _, response := range op.Responses {
+			names = append(names, ResponseName(op, response.Code))
+		}
+		return names
+	}, g.generateServer)
+}
+
+// generateOperations returns the Go types of the spec, the runtime shared by
+// the server and the client and the file generated from the operations
+func (g *Generator) generateOperations(operations []*generator.Operation, fileName string, identifiers []string, names func(op *generator.Operation) []string, generate func([]*generator.Operation) ([]byte, error)) ([]*generator.File, error) {
+	files, err := g.Generate()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if file.Path == fileName || file.Path == RUNTIME_FILE_NAME {
+			return nil, fmt.Errorf("[ERROR] %s is generated for both a model or schema file and the operations", file.Path)
+		}
+	}
+	if err := g.checkOperationNames(operations, identifiers, names); err != nil {
+		return nil, err
+	}
+
+	data, err := generate(operations)
+	if err != nil {
+		return nil, err
+	}
+	runtime, err := g.generateRuntime()
+	if err != nil {
+		return nil, err
+	}
+	return append(files, &generator.File{Path: fileName, Data: data}, &generator.File{Path: RUNTIME_FILE_NAME, Data: runtime}), nil
+}
+
+// checkOperationNames makes sure the names generated for the operations are not used by a type
+func (g *Generator) checkOperationNames(operations []*generator.Operation, identifiers []string, names func(op *generator.Operation) []string) error {
+	seen := map[string]string{}
+	for _, named := range g.Spec.Types() {
+		seen[g.TypeName(named)] = "type " + named.Location.String()
+	}
+	add := func(name, owner string) error {
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("[ERROR] Go name %s is used by both %s and %s", name, other, owner)
+		}
+		seen[name] = owner
+		return nil
+	}
+
+	for _, name := range identifiers {
+		if err := add(name, "the generated runtime"); err != nil {
+			return err
+		}
+	}
+	for _, op := range operations {
+		owner := "operation " + op.Method + " " + op.Path
+		for _, name := range names(op) {
+			if err := add(name, owner); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// generateRuntime returns the helpers shared by the server and the client
+func (g *Generator) generateRuntime() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(HEADER + "\n\n")
+	fmt.Fprintf(&b, "package %s\n", g.Package)
+	b.WriteString(sharedRuntime)
+	return formatSource(b.Bytes(), RUNTIME_FILE_NAME)
+}
+
+// writeImports writes the import block of a file generated from the operations
+func writeImports(b *bytes.Buffer, imports map[string]bool) {
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	b.WriteString("\nimport (\n")
+	for _, path := range paths {
+		fmt.Fprintf(b, "\t%q\n", path)
+	}
+	b.WriteString(")\n")
+}
+
+func (g *Generator) generateServer(operations []*generator.Operation) ([]byte, error) {
+	imports := map[string]bool{
+		"encoding/base64": true,
+		"encoding/json":   true,
+		"errors":          true,
+		"fmt":             true,
+		"io":              true,
+		"net/http":        true,
+		"reflect":         true,
+		"strconv":         true,
+		"strings":         true,
+		"time":            true,
+	}
+	if len(operations) > 0 {
+		imports["context"] = true
+	}
+
+	var body bytes.Buffer
+	body.WriteString("\n// Server is implemented by the handlers of every operation\ntype Server interface {\n")
+	for _, op := range operations {
+		if op.Description != "" {
+			writeComment(&body, "\t", OperationName(op)+" "+op.Description)
+		}
+		fmt.Fprintf(&body, "\t%s(ctx context.Context, input *%s) (*Response, error)\n", OperationName(op), InputName(op))
+	}
+	body.WriteString("}\n")
+
+	for _, op := range operations {
+		g.writeInput(&body, op, imports)
+		g.writeResponseHelpers(&body, op, imports)
+	}
+
+	body.WriteString(`
+// RegisterRoutes registers every operation of server on mux.
+// A nil errorHandler is DefaultErrorHandler.
+func RegisterRoutes(mux *http.ServeMux, server Server, errorHandler ErrorHandler) {
+	if errorHandler == nil {
+		errorHandler = DefaultErrorHandler
+	}
+	h := &serverHandler{server: server, errorHandler: errorHandler}
+`)
+	for _, op := range operations {
+		pattern, err := Pattern(op)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&body, "\tmux.HandleFunc(%q, h.handle%s)\n", pattern, OperationName(op))
+	}
+	body.WriteString("}\n")
+
+	for _, op := range operations {
+		g.writeHandler(&body, op)
+	}
+	body.WriteString(serverRuntime)
+
+	var b bytes.Buffer
+	b.WriteString(HEADER + "\n\n")
+	fmt.Fprintf(&b, "package %s\n", g.Package)
+	writeImports(&b, imports)
+	b.Write(body.Bytes())
+
+	return formatSource(b.Bytes(), SERVER_FILE_NAME)
+}
+
+func (g *Generator) writeInput(b *bytes.Buffer, op *generator.Operation, imports map[string]bool) {
+	fmt.Fprintf(b, "\n// %s is the decoded request of %s\ntype %s struct {\n", InputName(op), OperationName(op), InputName(op))
+	fields := inputFields(op)
+	for i, param := range op.Parameters {
+		fmt.Fprintf(b, "\t// %s is the %s parameter %s\n", fields[i], param.In, param.Name)
+		fmt.Fprintf(b, "\t%s %s\n", fields[i], g.ParamType(param.Type, param.Required, imports))
+	}
+	if op.Body != nil {
+		if op.Body.Description != "" {
+			writeComment(b, "\t", op.Body.Description)
+		}
+		fmt.Fprintf(b, "\t%s %s\n", BODY_FIELD, g.BodyType(op.Body.MediaType, op.Body.Type, op.Body.Required, imports))
+	}
+	fmt.Fprintf(b, "\t// %s is the request being served\n", HTTP_REQUEST_FIELD)
+	fmt.Fprintf(b, "\t%s *http.Request\n", HTTP_REQUEST_FIELD)
+	b.WriteString("}\n")
+}
+
+func (g *Generator) writeResponseHelpers(b *bytes.Buffer, op *generator.Operation, imports map[string]bool) {
+	for _, response := range op.Responses {
+		name := ResponseName(op, response.Code)
+		description := response.Description
+		if description == "" {
+			description = response.Code + " response"
+		}
+
+		params := []string{}
+		status := response.Code
+		if !generator.IsFixedStatus(response.Code) {
+			params = append(params, "status int")
+			status = "status"
+		}
+		if response.Type != nil {
+			params = append(params, "body "+g.BodyType(response.MediaType, response.Type, true, imports))
+		}
+
+		b.WriteString("\n")
+		writeComment(b, "", name+" "+description)
+		fmt.Fprintf(b, "func %s(%s) *Response {\n", name, strings.Join(params, ", "))
+		if response.Type == nil {
+			fmt.Fprintf(b, "\treturn &Response{Status: %s}\n", status)
+		} else {
+			fmt.Fprintf(b, "\treturn &Response{Status: %s, ContentType: %q, Body: body}\n", status, response.MediaType)
+		}
+		b.WriteString("}\n")
+	}
+}
+
+func (g *Generator) writeHandler(b *bytes.Buffer, op *generator.Operation) {
+	fmt.Fprintf(b, "\nfunc (h *serverHandler) handle%s(w http.ResponseWriter, r *http.Request) {\n", OperationName(op))
+	fmt.Fprintf(b, "\tinput := &%s{%s: r}\n", InputName(op), HTTP_REQUEST_FIELD)
+	fields := inputFields(op)
+	for i, param := range op.Parameters {
+		required := param.Required
+		if param.In == constants.PARAM_IN_PATH && !strings.Contains(op.Path, "{"+param.Name+"}") {
+			// never set, so requiring it would reject every request
+			fmt.Printf("[WARN] path parameter %s of %s %s is not part of the path\n", param.Name, op.Method, op.Path)
+			required = false
+		}
+		fmt.Fprintf(b, "\tif err := decodeParam(r, %q, %q, %s, &input.%s); err != nil {\n", param.In, param.Name, strconv.FormatBool(required), fields[i])
+		b.WriteString("\t\th.errorHandler(w, r, err)\n\t\treturn\n\t}\n")
+	}
+	if op.Body != nil {
+		fmt.Fprintf(b, "\tif err := decodeBody(r, %q, %s, &input.%s); err != nil {\n", op.Body.MediaType, strconv.FormatBool(op.Body.Required), BODY_FIELD)
+		b.WriteString("\t\th.errorHandler(w, r, err)\n\t\treturn\n\t}\n")
+	}
+	fmt.Fprintf(b, "\tres, err := h.server.%s(r.Context(), input)\n", OperationName(op))
+	b.WriteString("\th.respond(w, r, res, err)\n}\n")
+}
+
+// serverRuntime decodes requests and writes responses for the generated handlers
+const serverRuntime = `
+// Response is the status code, headers and body an operation responds with.
+// Body is encoded as JSON for JSON media types and written as is when it is
+// []byte, a string or an io.Reader.
+type Response struct {
+	Status      int
+	Header      http.Header
+	ContentType string
+	Body        any
+}
+
+// RequestError is returned when a parameter or the body of a request cannot be decoded
+type RequestError struct {
+	In   string // path, query, header, cookie or body
+	Name string
+	Err  error
+}
+
+func (e *RequestError) Error() string {
+	if e.In == "body" {
+		return fmt.Sprintf("invalid request body: %v", e.Err)
+	}
+	return fmt.Sprintf("invalid %s parameter %s: %v", e.In, e.Name, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorHandler writes the response of a request which could not be decoded or whose handler failed
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// DefaultErrorHandler responds 400 to requests which could not be decoded and 500 otherwise
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+type serverHandler struct {
+	server       Server
+	errorHandler ErrorHandler
+}
+
+func (h *serverHandler) respond(w http.ResponseWriter, r *http.Request, res *Response, err error) {
+	if err == nil && res == nil {
+		err = errors.New("handler returned no response")
+	}
+	if err != nil {
+		h.errorHandler(w, r, err)
+		return
+	}
+
+	var body []byte
+	if res.Body != nil {
+		switch content := res.Body.(type) {
+		case []byte:
+			body = content
+		case string:
+			body = []byte(content)
+		case io.Reader:
+			if body, err = io.ReadAll(content); err != nil {
+				h.errorHandler(w, r, err)
+				return
+			}
+		default:
+			if body, err = json.Marshal(content); err != nil {
+				h.errorHandler(w, r, err)
+				return
+			}
+		}
+	}
+
+	for name, values := range res.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	if res.Body != nil && res.ContentType != "" {
+		w.Header().Set("Content-Type", res.ContentType)
+	}
+	w.WriteHeader(res.Status)
+	if len(body) > 0 {
+		w.Write(body)
+	}
+}
+
+func paramValues(r *http.Request, in, name string) []string {
+	switch in {
+	case "path":
+		if value := r.PathValue(name); value != "" {
+			return []string{value}
+		}
+	case "query":
+		return r.URL.Query()[name]
+	case "header":
+		return r.Header.Values(name)
+	case "cookie":
+		if cookie, err := r.Cookie(name); err == nil {
+			return []string{cookie.Value}
+		}
+	}
+	return nil
+}
+
+// decodeParam decodes a parameter into dst, a pointer to the field of the input struct
+func decodeParam(r *http.Request, in, name string, required bool, dst any) error {
+	values := paramValues(r, in, name)
+	if len(values) == 0 {
+		if required {
+			return &RequestError{In: in, Name: name, Err: errors.New("missing required parameter")}
+		}
+		return nil
+	}
+
+	v := reflect.ValueOf(dst).Elem()
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	if err := decodeValue(values, v); err != nil {
+		return &RequestError{In: in, Name: name, Err: err}
+	}
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func decodeValue(values []string, v reflect.Value) error {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		if len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+		items := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			item := items.Index(i)
+			if item.Kind() == reflect.Pointer {
+				item.Set(reflect.New(item.Type().Elem()))
+				item = item.Elem()
+			}
+			if err := decodeValue([]string{value}, item); err != nil {
+				return err
+			}
+		}
+		v.Set(items)
+		return nil
+	}
+
+	value := values[0]
+	if v.Type() == timeType {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(value))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		data, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return err
+		}
+		v.SetBytes(data)
+	default:
+		return json.Unmarshal([]byte(value), v.Addr().Interface())
+	}
+	return nil
+}
+
+// decodeBody decodes the request body into dst, content which is not JSON is read into a *[]byte
+func decodeBody(r *http.Request, mediaType string, required bool, dst any) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return &RequestError{In: "body", Err: err}
+	}
+	if len(data) == 0 {
+		if required {
+			return &RequestError{In: "body", Err: errors.New("missing required body")}
+		}
+		return nil
+	}
+
+	if raw, ok := dst.(*[]byte); ok && !isJSONMediaType(mediaType) {
+		*raw = data
+		return nil
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return &RequestError{In: "body", Err: err}
+	}
+	return nil
+}
+`
+
+// sharedRuntime are the helpers of both the server and the client
+const sharedRuntime = `
+import "strings"
+
+func isJSONMediaType(mediaType string) bool {
+	base, _, _ := strings.Cut(mediaType, ";")
+	base = strings.TrimSpace(base)
+	return base == "application/json" || strings.HasSuffix(base, "+json")
+}
+`
@@ -0,0 +1,198 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+const (
+	FORMAT_DOT     = "dot"
+	FORMAT_MERMAID = "mermaid"
+
+	// GRANULARITY_FILE has a node per file and an edge per referencing file pair
+	GRANULARITY_FILE = "file"
+	// GRANULARITY_FIELD has a node per $ref location and $ref target, grouped by file
+	GRANULARITY_FIELD = "field"
+)
+
+// Node is a file, or a location inside a file at field granularity
+type Node struct {
+	ID    string // file or <file>#<pointer>
+	File  string
+	Label string
+}
+
+type Edge struct {
+	From string // node IDs
+	To   string
+}
+
+// Graph is the $ref dependency graph of a project
+type Graph struct {
+	Granularity string
+	Nodes       []*Node // sorted by ID
+	Edges       []Edge
+}
+
+// Build returns the $ref graph of a project. With from, only what is reachable
+// from that file is kept; with to, only what reaches that file.
+func Build(project *loader.Project, granularity, from, to string) (*Graph, error) {
+	if granularity != GRANULARITY_FILE && granularity != GRANULARITY_FIELD {
+		return nil, fmt.Errorf("[ERROR] unknown granularity %q: use %s or %s", granularity, GRANULARITY_FILE, GRANULARITY_FIELD)
+	}
+
+	refs := project.References()
+	g := &Graph{Granularity: granularity}
+	nodes := map[string]*Node{}
+	addNode := func(t loader.Target) string {
+		node := &Node{ID: t.File, File: t.File, Label: t.File}
+		if granularity == GRANULARITY_FIELD {
+			node.ID = t.String()
+			node.Label = t.String()[len(t.File):]
+		}
+		if _, ok := nodes[node.ID]; !ok {
+			nodes[node.ID] = node
+			g.Nodes = append(g.Nodes, node)
+		}
+		return node.ID
+	}
+
+	filtered := from != "" || to != ""
+	for _, filter := range []struct {
+		file    string
+		forward bool
+	}{{from, true}, {to, false}} {
+		if filter.file == "" {
+			continue
+		}
+		target := loader.ParseTarget(filter.file)
+		if _, ok := project.Documents[target.File]; !ok {
+			return nil, fmt.Errorf("[ERROR] file not found: %s", target.File)
+		}
+		refs = loader.Reachable(refs, target, filter.forward)
+		if granularity == GRANULARITY_FILE {
+			addNode(target)
+		}
+	}
+	if !filtered && granularity == GRANULARITY_FILE {
+		// files without any $ref are part of the picture too
+		for file := range project.Documents {
+			addNode(loader.Target{File: file})
+		}
+	}
+
+	edges := map[Edge]bool{}
+	for _, ref := range refs {
+		edge := Edge{From: addNode(ref.From), To: addNode(ref.To)}
+		if edge.From == edge.To || edges[edge] {
+			continue
+		}
+		edges[edge] = true
+		g.Edges = append(g.Edges, edge)
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g, nil
+}
+
+// Render returns the graph in Graphviz DOT or Mermaid syntax
+func (g *Graph) Render(format string) ([]byte, error) {
+	switch format {
+	case FORMAT_DOT:
+		return g.DOT(), nil
+	case FORMAT_MERMAID:
+		return g.Mermaid(), nil
+	}
+	return nil, fmt.Errorf("[ERROR] unknown format %q: use %s or %s", format, FORMAT_DOT, FORMAT_MERMAID)
+}
+
+func (g *Graph) DOT() []byte {
+	var b strings.Builder
+	b.WriteString("digraph swagen {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for i, file := range g.files() {
+		if g.Granularity == GRANULARITY_FIELD {
+			fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+			fmt.Fprintf(&b, "    label=%s;\n", dotQuote(file))
+			for _, node := range g.nodesOf(file) {
+				fmt.Fprintf(&b, "    %s [label=%s];\n", dotQuote(node.ID), dotQuote(node.Label))
+			}
+			b.WriteString("  }\n")
+			continue
+		}
+		fmt.Fprintf(&b, "  %s;\n", dotQuote(file))
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(edge.From), dotQuote(edge.To))
+	}
+	b.WriteString("}\n")
+	return []byte(b.String())
+}
+
+func (g *Graph) Mermaid() []byte {
+	ids := map[string]string{}
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for i, file := range g.files() {
+		if g.Granularity == GRANULARITY_FIELD {
+			fmt.Fprintf(&b, "  subgraph f%d[%s]\n", i, mermaidQuote(file))
+			for _, node := range g.nodesOf(file) {
+				fmt.Fprintf(&b, "    %s[%s]\n", ids[node.ID], mermaidQuote(node.Label))
+			}
+			b.WriteString("  end\n")
+			continue
+		}
+		fmt.Fprintf(&b, "  %s[%s]\n", ids[file], mermaidQuote(file))
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+	}
+	return []byte(b.String())
+}
+
+// files returns the files of the nodes in order
+func (g *Graph) files() []string {
+	files := []string{}
+	seen := map[string]bool{}
+	for _, node := range g.Nodes {
+		if !seen[node.File] {
+			seen[node.File] = true
+			files = append(files, node.File)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func (g *Graph) nodesOf(file string) []*Node {
+	nodes := []*Node{}
+	for _, node := range g.Nodes {
+		if node.File == file {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// mermaidQuote quotes a label, Mermaid has entity codes instead of escapes
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,311 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/generator"
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+const (
+	FILE_EXT         = ".json"
+	DIALECT          = "https://json-schema.org/draft/2020-12/schema"
+	DEFAULT_BASE_URI = "https://swagen.local/schemas/"
+	NULL_TYPE        = "null"
+	DEFS             = "$defs"
+)
+
+// Generator emits one JSON Schema 2020-12 document per model and schema root.
+// OpenAPI's nullable becomes a type union and relative file refs point at the
+// $id of the document defining their target. With Dereference every $ref is
+// inlined instead, so each document stands alone.
+type Generator struct {
+	Project     *loader.Project
+	BaseURI     string
+	Dereference bool
+}
+
+func NewGenerator(project *loader.Project, baseURI string, dereference bool) *Generator {
+	if baseURI != "" && !strings.HasSuffix(baseURI, "/") {
+		baseURI += "/"
+	}
+	return &Generator{
+		Project:     project,
+		BaseURI:     baseURI,
+		Dereference: dereference,
+	}
+}
+
+// DocumentPath returns the file a root is written to: model/user.json for
+// models and schema/<dir>/<RootName>.json for schema roots
+func (g *Generator) DocumentPath(root *loader.Root) string {
+	layout := g.Project.LayoutPath(root.Document)
+	if root.Name == "" {
+		return layout + FILE_EXT
+	}
+	return path.Join(path.Dir(layout), root.Name+FILE_EXT)
+}
+
+// ID returns the $id of a root's document
+func (g *Generator) ID(root *loader.Root) string {
+	return g.BaseURI + g.DocumentPath(root)
+}
+
+// Generate returns one document per model and schema root
+func (g *Generator) Generate() ([]*generator.File, error) {
+	files := []*generator.File{}
+	for _, root := range g.Project.Roots() {
+		doc, err := g.Document(root)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &generator.File{Path: g.DocumentPath(root), Data: append(data, '\n')})
+	}
+	return files, nil
+}
+
+// Document converts a root into a JSON Schema document
+func (g *Generator) Document(root *loader.Root) (map[string]interface{}, error) {
+	c := &converter{
+		generator: g,
+		root:      root.Target(),
+		inlining:  make(map[loader.Target]bool),
+		defKeys:   make(map[loader.Target]string),
+		defs:      make(map[string]interface{}),
+	}
+
+	doc, err := c.convert(root.Target(), root.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	doc["$schema"] = DIALECT
+	doc["$id"] = g.ID(root)
+	title := root.Name
+	if title == "" {
+		title = root.Document.Title
+	}
+	if title != "" {
+		doc["title"] = title
+	}
+	if len(c.defs) > 0 {
+		doc[DEFS] = c.defs
+	}
+	return doc, nil
+}
+
+// converter converts the schemas of one document
+type converter struct {
+	generator *Generator
+	root      loader.Target
+
+	inlining map[loader.Target]bool // refs being inlined, to detect recursion
+	defKeys  map[loader.Target]string
+	defs     map[string]interface{}
+}
+
+func (c *converter) convert(at loader.Target, schema *handler.Property) (map[string]interface{}, error) {
+	if schema == nil {
+		return map[string]interface{}{}, nil
+	}
+	if schema.Ref != "" {
+		return c.ref(at, schema.Ref)
+	}
+
+	out := map[string]interface{}{}
+	schemaType := schema.Type
+	if schemaType == "" && len(schema.Properties) > 0 {
+		schemaType = constants.OBJECT_TYPE
+	}
+	if schemaType != "" {
+		if schema.Nullable {
+			out["type"] = []string{schemaType, NULL_TYPE}
+		} else {
+			out["type"] = schemaType
+		}
+	}
+	if schema.Format != "" && schema.Format != constants.FORMAT_NONE {
+		out["format"] = schema.Format
+	}
+	if schema.Description != "" {
+		out["description"] = schema.Description
+	}
+	if len(schema.Enum) > 0 {
+		enum := append([]interface{}{}, schema.Enum...)
+		if schema.Nullable {
+			enum = append(enum, nil)
+		}
+		out["enum"] = enum
+	}
+	if schema.ReadOnly {
+		out["readOnly"] = true
+	}
+	if schema.Deprecated {
+		out["deprecated"] = true
+	}
+	if schema.MaxLength > 0 {
+		out["maxLength"] = schema.MaxLength
+	}
+	if schema.MinLength > 0 {
+		out["minLength"] = schema.MinLength
+	}
+	if schema.Pattern != "" {
+		out["pattern"] = schema.Pattern
+	}
+	if schema.Minimum != nil {
+		out["minimum"] = *schema.Minimum
+	}
+	if schema.Maximum != nil {
+		out["maximum"] = *schema.Maximum
+	}
+	if schema.MinItems > 0 {
+		out["minItems"] = schema.MinItems
+	}
+	if schema.MaxItems > 0 {
+		out["maxItems"] = schema.MaxItems
+	}
+	if schema.Example != "" {
+		out["examples"] = []interface{}{exampleValue(schemaType, schema.Example)}
+	}
+
+	if len(schema.Properties) > 0 {
+		properties := map[string]interface{}{}
+		for name, prop := range schema.Properties {
+			converted, err := c.convert(at.Child(strings.TrimPrefix(fetcher.PROPERTIES_PATH, "/"), name), prop)
+			if err != nil {
+				return nil, err
+			}
+			properties[name] = converted
+		}
+		out["properties"] = properties
+	}
+	if len(schema.Required) > 0 {
+		out["required"] = schema.Required
+	}
+	if schema.Items != nil {
+		items, err := c.convert(at.Child(fetcher.ITEMS_OPTION), schema.Items)
+		if err != nil {
+			return nil, err
+		}
+		out["items"] = items
+	}
+
+	return out, nil
+}
+
+func (c *converter) ref(at loader.Target, ref string) (map[string]interface{}, error) {
+	target, schema, err := c.generator.Project.ResolveRef(at.File, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.generator.Dereference {
+		id, err := c.idOf(target)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"$ref": id}, nil
+	}
+
+	if target == c.root {
+		return map[string]interface{}{"$ref": "#"}, nil
+	}
+	if c.inlining[target] {
+		// recursive, keep one copy under $defs
+		return map[string]interface{}{"$ref": "#/" + DEFS + "/" + c.defKey(target)}, nil
+	}
+
+	c.inlining[target] = true
+	converted, err := c.convert(target, schema)
+	delete(c.inlining, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, ok := c.defKeys[target]; ok {
+		c.defs[key] = converted
+		return map[string]interface{}{"$ref": "#/" + DEFS + "/" + key}, nil
+	}
+	return converted, nil
+}
+
+// idOf returns the $id based reference of a target: the $id of the document of
+// its root followed by the pointer below the root
+func (c *converter) idOf(target loader.Target) (string, error) {
+	root, tokens, err := c.generator.Project.RootOf(target)
+	if err != nil {
+		return "", err
+	}
+
+	id := c.generator.ID(root)
+	if len(tokens) == 0 {
+		return id, nil
+	}
+	return id + fetcher.JSON_POINTER_REF + root.Target().Child(tokens...).Pointer[len(root.Pointer()):], nil
+}
+
+func (c *converter) defKey(target loader.Target) string {
+	if key, ok := c.defKeys[target]; ok {
+		return key
+	}
+
+	parts := []string{}
+	if root, tokens, err := c.generator.Project.RootOf(target); err == nil {
+		parts = append(parts, root.Name, root.Document.Title)
+		properties := strings.TrimPrefix(fetcher.PROPERTIES_PATH, "/")
+		for _, token := range tokens {
+			if token != properties {
+				parts = append(parts, token)
+			}
+		}
+	}
+	key := generator.Pascal(parts...)
+	if key == "" {
+		key = "Def"
+	}
+	unique := key
+	for i := 2; c.hasKey(unique); i++ {
+		unique = key + strconv.Itoa(i)
+	}
+	c.defKeys[target] = unique
+	return unique
+}
+
+func (c *converter) hasKey(key string) bool {
+	for _, existing := range c.defKeys {
+		if existing == key {
+			return true
+		}
+	}
+	return false
+}
+
+// exampleValue parses an example of a number, integer or boolean schema so it has the schema's type
+func exampleValue(schemaType, example string) interface{} {
+	switch schemaType {
+	case constants.INTEGER_TYPE:
+		if v, err := strconv.ParseInt(example, 10, 64); err == nil {
+			return v
+		}
+	case constants.NUMBER_TYPE:
+		if v, err := strconv.ParseFloat(example, 64); err == nil {
+			return v
+		}
+	case constants.BOOLEAN_TYPE:
+		if v, err := strconv.ParseBool(example); err == nil {
+			return v
+		}
+	}
+	return example
+}
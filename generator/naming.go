@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Words splits an identifier into words on separators and case changes:
+// "user_profile" -> [user profile], "IDToken" -> [ID Token], "createdAt" -> [created At]
+func Words(s string) []string {
+	words := []string{}
+	current := []rune{}
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if len(current) > 0 && unicode.IsUpper(r) {
+			prev := current[len(current)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+
+	return words
+}
+
+// Pascal joins words with their first letter upper-cased: "user_profile" -> "UserProfile"
+func Pascal(parts ...string) string {
+	var b strings.Builder
+	for _, part := range parts {
+		for _, word := range Words(part) {
+			runes := []rune(word)
+			b.WriteRune(unicode.ToUpper(runes[0]))
+			b.WriteString(string(runes[1:]))
+		}
+	}
+	return b.String()
+}
+
+// Camel is Pascal with a lower-case first word: "user_profile" -> "userProfile"
+func Camel(parts ...string) string {
+	pascal := Pascal(parts...)
+	words := Words(pascal)
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.ToLower(words[0]) + strings.TrimPrefix(pascal, words[0])
+}
+
+// Snake joins lower-cased words with underscores: "UserProfile" -> "user_profile"
+func Snake(parts ...string) string {
+	words := []string{}
+	for _, part := range parts {
+		for _, word := range Words(part) {
+			words = append(words, strings.ToLower(word))
+		}
+	}
+	return strings.Join(words, "_")
+}
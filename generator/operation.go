@@ -0,0 +1,239 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+const (
+	DEFAULT_RESPONSE_CODE = "default"
+)
+
+// Operation is the language independent view of an operation of a path file.
+// Inline request and response schemas are named after the operation.
+type Operation struct {
+	*loader.Operation
+	Name        string
+	Parts       []string // words the name is built from, for language specific casing
+	Description string
+	Parameters  []*Parameter
+	Body        *Body
+	Responses   []*Response
+}
+
+// Parameter is a path, query, header or cookie parameter
+type Parameter struct {
+	Name     string
+	In       string
+	Required bool
+	Type     *TypeRef
+}
+
+// Body is the request body of an operation in its preferred media type
+type Body struct {
+	MediaType   string
+	Required    bool
+	Description string
+	Type        *TypeRef
+}
+
+// Response is a documented status code, Type is nil when it has no content
+type Response struct {
+	Code        string // "200", "4XX" or "default"
+	Description string
+	MediaType   string
+	Type        *TypeRef
+}
+
+// IsJSONMediaType reports whether content of the media type is JSON encoded
+func IsJSONMediaType(mediaType string) bool {
+	base, _, _ := strings.Cut(mediaType, ";")
+	base = strings.TrimSpace(base)
+	return base == constants.APPLICATION_JSON || strings.HasSuffix(base, "+json")
+}
+
+// PreferredMediaType picks the media type used for generated code: JSON
+// when offered, otherwise the first one in alphabetical order
+func PreferredMediaType[T any](content map[string]T) string {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	for _, mediaType := range mediaTypes {
+		if IsJSONMediaType(mediaType) {
+			return mediaType
+		}
+	}
+	if len(mediaTypes) == 0 {
+		return ""
+	}
+	return mediaTypes[0]
+}
+
+// IsFixedStatus reports whether a response code is a single status code
+// rather than a range like "4XX" or "default"
+func IsFixedStatus(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// StatusClass returns the leading digit of a status code range like "4XX"
+func StatusClass(code string) (int, bool) {
+	if len(code) != 3 || code[0] < '1' || code[0] > '5' || strings.ToUpper(code[1:]) != "XX" {
+		return 0, false
+	}
+	return int(code[0] - '0'), true
+}
+
+// Operations returns every operation of the project. Inline request and
+// response schemas are added to the spec as types of the path file modules.
+func (s *Spec) Operations() ([]*Operation, error) {
+	loaded, err := s.Project.Operations()
+	if err != nil {
+		return nil, err
+	}
+
+	operations := []*Operation{}
+	names := map[string]*Operation{}
+	for _, lo := range loaded {
+		op, err := s.operation(lo)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := names[op.Name]; ok {
+			return nil, fmt.Errorf("[ERROR] operation name %s is used by both %s %s and %s %s", op.Name, other.Method, other.Path, op.Method, op.Path)
+		}
+		names[op.Name] = op
+		operations = append(operations, op)
+	}
+
+	s.sortModules()
+	return operations, nil
+}
+
+// operationParts names an operation after its operationId, or after its
+// method and path when it has none: get /users/{id} -> get users by id
+func operationParts(lo *loader.Operation) []string {
+	if lo.API.OperationID != "" {
+		return []string{lo.API.OperationID}
+	}
+
+	parts := []string{lo.Method}
+	for _, segment := range strings.Split(strings.Trim(lo.Path, "/"), "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			parts = append(parts, "by", strings.Trim(segment, "{}"))
+			continue
+		}
+		if segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+	return parts
+}
+
+func (s *Spec) operation(lo *loader.Operation) (*Operation, error) {
+	parts := operationParts(lo)
+	op := &Operation{
+		Operation:   lo,
+		Name:        Pascal(parts...),
+		Parts:       parts,
+		Description: lo.API.Summary,
+	}
+	if op.Name == "" {
+		return nil, fmt.Errorf("[ERROR] no name for the operation %s %s", lo.Method, lo.Path)
+	}
+	if op.Description == "" {
+		op.Description = lo.API.Description
+	}
+	at := loader.Target{File: lo.Document.File, Pointer: lo.Pointer()}
+
+	for i, param := range lo.API.Parameters {
+		if param == nil {
+			continue
+		}
+		t, err := s.typeAt(append(append([]string{}, parts...), param.Name), at.Child("parameters", fmt.Sprint(i), "schema"), loader.ParameterSchema(param))
+		if err != nil {
+			return nil, err
+		}
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name: param.Name,
+			In:   param.In,
+			// path parameters are always required
+			Required: param.Required || param.In == constants.PARAM_IN_PATH,
+			Type:     t,
+		})
+	}
+
+	if body := lo.API.RequestBody; body != nil {
+		if mediaType := PreferredMediaType(body.Content); mediaType != "" {
+			var schema *handler.Property
+			if content := body.Content[mediaType]; content != nil {
+				schema = content.Schema
+			}
+			t, err := s.typeAt(append(append([]string{}, parts...), "request", "body"), at.Child("requestBody", "content", mediaType, "schema"), schema)
+			if err != nil {
+				return nil, err
+			}
+			op.Body = &Body{MediaType: mediaType, Required: body.Required, Description: body.Description, Type: t}
+		}
+	}
+
+	codes := make([]string, 0, len(lo.API.Responses))
+	for code := range lo.API.Responses {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		// default goes last
+		if (codes[i] == DEFAULT_RESPONSE_CODE) != (codes[j] == DEFAULT_RESPONSE_CODE) {
+			return codes[j] == DEFAULT_RESPONSE_CODE
+		}
+		return codes[i] < codes[j]
+	})
+	for _, code := range codes {
+		res := lo.API.Responses[code]
+		response := &Response{Code: code}
+		if res != nil {
+			response.Description = res.Description
+			if mediaType := PreferredMediaType(res.Content); mediaType != "" {
+				var schema *handler.Property
+				if content := res.Content[mediaType]; content != nil {
+					schema = content.Schema
+				}
+				t, err := s.typeAt(append(append([]string{}, parts...), code, "response"), at.Child("responses", code, "content", mediaType, "schema"), schema)
+				if err != nil {
+					return nil, err
+				}
+				response.MediaType = mediaType
+				response.Type = t
+			}
+		}
+		op.Responses = append(op.Responses, response)
+	}
+
+	return op, nil
+}
+
+// typeAt returns the type of a schema of a path file; objects and enums
+// defined inline are named after parts
+func (s *Spec) typeAt(parts []string, at loader.Target, schema *handler.Property) (*TypeRef, error) {
+	if schema == nil {
+		return &TypeRef{}, nil
+	}
+	if !s.isRoot(at) {
+		s.anchors = append(s.anchors, &anchor{Target: at, Parts: parts})
+	}
+	return s.TypeOf(at, schema)
+}
@@ -0,0 +1,121 @@
+package proto
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	LOCK_FILE_NAME = "swagen.proto.lock.yaml"
+
+	// field numbers 19000 to 19999 are reserved by the protobuf implementation
+	RESERVED_RANGE_START = 19000
+	RESERVED_RANGE_END   = 19999
+)
+
+// Lock keeps the field numbers of every message and the values of every enum
+// so re-generation never renumbers. Entries of removed fields stay in the lock
+// and are emitted as reserved, so their numbers are never reused.
+type Lock struct {
+	Messages map[string]map[string]int `yaml:"messages,omitempty"`
+	Enums    map[string]map[string]int `yaml:"enums,omitempty"`
+}
+
+// LoadLock reads the lock file, a missing file is an empty lock
+func LoadLock(path string) (*Lock, error) {
+	lock := &Lock{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("[ERROR] failed to parse lock file %s: %v", path, err)
+	}
+	return lock, nil
+}
+
+// Save writes the lock file
+func (l *Lock) Save(path string) error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("[INFO] Wrote %s\n", path)
+	return nil
+}
+
+// numbers assigns a number to every name, keeping the numbers already locked.
+// New names get the next free numbers in the given order starting after start.
+func numbers(locked map[string]int, names []string, start int) map[string]int {
+	next := start
+	for _, number := range locked {
+		if number > next {
+			next = number
+		}
+	}
+
+	for _, name := range names {
+		if _, ok := locked[name]; ok {
+			continue
+		}
+		next++
+		if next >= RESERVED_RANGE_START && next <= RESERVED_RANGE_END {
+			next = RESERVED_RANGE_END + 1
+		}
+		locked[name] = next
+	}
+	return locked
+}
+
+// removed returns the locked names which are no longer used, ordered by number
+func removed(locked map[string]int, names []string) []string {
+	used := make(map[string]bool, len(names))
+	for _, name := range names {
+		used[name] = true
+	}
+
+	unused := []string{}
+	for name := range locked {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool {
+		return locked[unused[i]] < locked[unused[j]]
+	})
+	return unused
+}
+
+func (l *Lock) messageNumbers(message string, fields []string) map[string]int {
+	if l.Messages == nil {
+		l.Messages = make(map[string]map[string]int)
+	}
+	if l.Messages[message] == nil {
+		l.Messages[message] = make(map[string]int)
+	}
+	return numbers(l.Messages[message], fields, 0)
+}
+
+func (l *Lock) enumNumbers(enum string, values []string) map[string]int {
+	if l.Enums == nil {
+		l.Enums = make(map[string]map[string]int)
+	}
+	if l.Enums[enum] == nil {
+		l.Enums[enum] = make(map[string]int)
+	}
+	// 0 is the UNSPECIFIED value
+	return numbers(l.Enums[enum], values, 0)
+}
@@ -0,0 +1,337 @@
+package proto
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/generator"
+)
+
+const (
+	FILE_EXT        = ".proto"
+	HEADER          = "// Code generated by swagen-v2. DO NOT EDIT."
+	DEFAULT_PACKAGE = "api"
+
+	TIMESTAMP_PROTO = "google/protobuf/timestamp.proto"
+	STRUCT_PROTO    = "google/protobuf/struct.proto"
+
+	// field of the message wrapping a schema root which is not an object
+	WRAPPER_FIELD = "value"
+)
+
+// Generator emits proto3 messages and enums, one .proto file per model or
+// schema file laid out like the project directories
+type Generator struct {
+	Spec    *generator.Spec
+	Package string
+	Lock    *Lock
+}
+
+func NewGenerator(spec *generator.Spec, packageName string, lock *Lock) *Generator {
+	return &Generator{
+		Spec:    spec,
+		Package: packageName,
+		Lock:    lock,
+	}
+}
+
+// FieldName returns the snake_case name of a JSON property
+func FieldName(name string) string {
+	field := generator.Snake(name)
+	if field == "" || field[0] >= '0' && field[0] <= '9' {
+		field = "field_" + field
+	}
+	return field
+}
+
+// jsonName is the JSON name protoc derives from a snake_case field name
+func jsonName(field string) string {
+	parts := strings.Split(field, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// target follows aliases of named types until a type with a definition is reached
+func target(ref *generator.TypeRef) *generator.TypeRef {
+	for ref.Named != nil && ref.Named.Kind == generator.KIND_ALIAS && ref.Named.Alias.Named != nil {
+		ref = ref.Named.Alias
+	}
+	return ref
+}
+
+// isEnum reports whether a named type becomes a proto enum; only string enums
+// do, other enums keep their scalar type
+func isEnum(named *generator.NamedType) bool {
+	return named.Kind == generator.KIND_ENUM && named.EnumType == constants.STRING_TYPE
+}
+
+type fileState struct {
+	module  *generator.Module
+	imports map[string]bool
+}
+
+// TypeExpr returns the proto type of a single value and whether it is a message
+func (g *Generator) TypeExpr(ref *generator.TypeRef, fs *fileState) (string, bool) {
+	ref = target(ref)
+	if named := ref.Named; named != nil {
+		if named.Kind == generator.KIND_ENUM && !isEnum(named) {
+			return g.TypeExpr(&generator.TypeRef{Type: named.EnumType, Format: named.Schema.Format}, fs)
+		}
+		if named.Module != fs.module {
+			fs.imports[g.Spec.ModulePath(named.Module)+FILE_EXT] = true
+		}
+		return named.Name, !isEnum(named)
+	}
+
+	switch ref.Type {
+	case constants.STRING_TYPE:
+		switch ref.Format {
+		case constants.FORMAT_DATE_TIME:
+			fs.imports[TIMESTAMP_PROTO] = true
+			return "google.protobuf.Timestamp", true
+		case constants.FORMAT_BYTE, constants.FORMAT_BINARY:
+			return "bytes", false
+		}
+		return "string", false
+	case constants.INTEGER_TYPE:
+		if ref.Format == constants.FORMAT_INT32 {
+			return "int32", false
+		}
+		return "int64", false
+	case constants.NUMBER_TYPE:
+		if ref.Format == constants.FORMAT_FLOAT {
+			return "float", false
+		}
+		return "double", false
+	case constants.BOOLEAN_TYPE:
+		return "bool", false
+	case constants.ARRAY_TYPE:
+		// repeated fields cannot be nested
+		fs.imports[STRUCT_PROTO] = true
+		return "google.protobuf.ListValue", true
+	case constants.OBJECT_TYPE:
+		fs.imports[STRUCT_PROTO] = true
+		return "google.protobuf.Struct", true
+	}
+	fs.imports[STRUCT_PROTO] = true
+	return "google.protobuf.Value", true
+}
+
+// FieldType returns the label and type of a field
+func (g *Generator) FieldType(ref *generator.TypeRef, required bool, fs *fileState) string {
+	ref = target(ref)
+	if ref.Named == nil && ref.Type == constants.ARRAY_TYPE {
+		items := &generator.TypeRef{}
+		if ref.Items != nil {
+			items = ref.Items
+		}
+		expr, _ := g.TypeExpr(items, fs)
+		return "repeated " + expr
+	}
+
+	expr, isMessage := g.TypeExpr(ref, fs)
+	if !isMessage && (!required || ref.Nullable) {
+		return "optional " + expr
+	}
+	return expr
+}
+
+// Generate returns one .proto file per model or schema file
+func (g *Generator) Generate() ([]*generator.File, error) {
+	files := []*generator.File{}
+	for _, module := range g.Spec.Modules {
+		files = append(files, &generator.File{
+			Path: g.Spec.ModulePath(module) + FILE_EXT,
+			Data: g.generateModule(module),
+		})
+	}
+	return files, nil
+}
+
+func (g *Generator) generateModule(module *generator.Module) []byte {
+	fs := &fileState{module: module, imports: make(map[string]bool)}
+	var body bytes.Buffer
+	for _, named := range module.Types {
+		g.writeType(&body, named, fs)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(HEADER + "\n")
+	fmt.Fprintf(&b, "// Source: %s\n\n", filepath.ToSlash(module.Document.File))
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n", g.Package)
+
+	if len(fs.imports) > 0 {
+		paths := make([]string, 0, len(fs.imports))
+		for path := range fs.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		b.WriteString("\n")
+		for _, path := range paths {
+			fmt.Fprintf(&b, "import %q;\n", path)
+		}
+	}
+
+	b.Write(body.Bytes())
+	return b.Bytes()
+}
+
+func writeComment(b *bytes.Buffer, indent, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Fprintf(b, "%s// %s\n", indent, strings.TrimRight(line, " \t\r"))
+	}
+}
+
+// protoField is a field of a message before it is numbered
+type protoField struct {
+	name        string // JSON name, the key in the lock file
+	typ         string
+	description string
+}
+
+func (g *Generator) writeType(b *bytes.Buffer, named *generator.NamedType, fs *fileState) {
+	switch named.Kind {
+	case generator.KIND_OBJECT:
+		fields := make([]*protoField, 0, len(named.Fields))
+		for _, field := range named.Fields {
+			fields = append(fields, &protoField{
+				name:        field.Name,
+				typ:         g.FieldType(field.Type, field.Required, fs),
+				description: field.Description,
+			})
+		}
+		g.writeMessage(b, named, fields)
+	case generator.KIND_ENUM:
+		if isEnum(named) {
+			g.writeEnum(b, named)
+		}
+	case generator.KIND_ALIAS:
+		if named.Alias.Named != nil {
+			// references use the aliased message directly
+			return
+		}
+		g.writeMessage(b, named, []*protoField{{
+			name: WRAPPER_FIELD,
+			typ:  g.FieldType(named.Alias, true, fs),
+		}})
+	}
+}
+
+func (g *Generator) writeMessage(b *bytes.Buffer, named *generator.NamedType, fields []*protoField) {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.name)
+	}
+	locked := g.Lock.messageNumbers(named.Name, names)
+	sort.SliceStable(fields, func(i, j int) bool {
+		return locked[fields[i].name] < locked[fields[j].name]
+	})
+
+	b.WriteString("\n")
+	if named.Description != "" {
+		writeComment(b, "", named.Description)
+	}
+	fmt.Fprintf(b, "message %s {\n", named.Name)
+	writeReserved(b, locked, names, FieldName)
+
+	used := map[string]bool{}
+	for _, field := range fields {
+		if field.description != "" {
+			writeComment(b, "  ", field.description)
+		}
+		name := FieldName(field.name)
+		for i := 2; used[name]; i++ {
+			name = FieldName(field.name) + "_" + strconv.Itoa(i)
+		}
+		used[name] = true
+		options := ""
+		if jsonName(name) != field.name {
+			options = fmt.Sprintf(" [json_name = %q]", field.name)
+		}
+		fmt.Fprintf(b, "  %s %s = %d%s;\n", field.typ, name, locked[field.name], options)
+	}
+	b.WriteString("}\n")
+}
+
+func (g *Generator) writeEnum(b *bytes.Buffer, named *generator.NamedType) {
+	values := make([]string, 0, len(named.Enum))
+	for _, value := range named.Enum {
+		values = append(values, fmt.Sprint(value))
+	}
+	locked := g.Lock.enumNumbers(named.Name, values)
+	prefix := strings.ToUpper(generator.Snake(named.Name)) + "_"
+
+	b.WriteString("\n")
+	if named.Description != "" {
+		writeComment(b, "", named.Description)
+	}
+	fmt.Fprintf(b, "enum %s {\n", named.Name)
+	writeReserved(b, locked, values, func(value string) string {
+		return enumValueName(prefix, value)
+	})
+	fmt.Fprintf(b, "  %sUNSPECIFIED = 0;\n", prefix)
+
+	sort.SliceStable(values, func(i, j int) bool {
+		return locked[values[i]] < locked[values[j]]
+	})
+	used := map[string]bool{prefix + "UNSPECIFIED": true}
+	for _, value := range values {
+		name := enumValueName(prefix, value)
+		for i := 2; used[name]; i++ {
+			name = prefix + strings.ToUpper(generator.Snake(value)) + "_" + strconv.Itoa(i)
+		}
+		used[name] = true
+		fmt.Fprintf(b, "  %s = %d;\n", name, locked[value])
+	}
+	b.WriteString("}\n")
+}
+
+// enumValueName is the proto name of an enum value
+func enumValueName(prefix, value string) string {
+	name := strings.ToUpper(generator.Snake(value))
+	if name == "" {
+		name = "EMPTY"
+	}
+	return prefix + name
+}
+
+// writeReserved reserves the numbers and the names of removed fields so they
+// are never reused. current are the names still in use and identifier gives
+// the proto name of a field.
+func writeReserved(b *bytes.Buffer, locked map[string]int, current []string, identifier func(string) string) {
+	unused := removed(locked, current)
+	if len(unused) == 0 {
+		return
+	}
+	numbers := make([]string, 0, len(unused))
+	for _, name := range unused {
+		numbers = append(numbers, strconv.Itoa(locked[name]))
+	}
+	fmt.Fprintf(b, "  reserved %s;\n", strings.Join(numbers, ", "))
+
+	// a name still taken by a current field, e.g. after a json name changed case, cannot be reserved
+	taken := map[string]bool{}
+	for _, name := range current {
+		taken[identifier(name)] = true
+	}
+	names := []string{}
+	for _, name := range unused {
+		if id := identifier(name); !taken[id] {
+			taken[id] = true
+			names = append(names, strconv.Quote(id))
+		}
+	}
+	if len(names) > 0 {
+		fmt.Fprintf(b, "  reserved %s;\n", strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,411 @@
+package python
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/generator"
+)
+
+const (
+	FILE_EXT  = ".py"
+	INIT_FILE = "__init__.py"
+	HEADER    = "# Code generated by swagen-v2. DO NOT EDIT."
+	INDENT    = "    "
+)
+
+// keywords cannot be used as module or attribute names
+var keywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true, "def": true,
+	"del": true, "elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// shadowing names are attributes of BaseModel or names used in annotations,
+// which a field of the same name would hide inside the class body
+var shadowing = map[string]bool{
+	"construct": true, "copy": true, "dict": true, "fields": true, "json": true,
+	"parse_obj": true, "schema": true, "validate": true,
+	"bool": true, "bytes": true, "date": true, "datetime": true, "float": true,
+	"int": true, "str": true,
+}
+
+// Generator emits pydantic v2 models, one module per model or schema file
+// laid out like the project directories
+type Generator struct {
+	Spec *generator.Spec
+}
+
+func NewGenerator(spec *generator.Spec) *Generator {
+	return &Generator{
+		Spec: spec,
+	}
+}
+
+// ModulePath returns the slash separated module path of a module, e.g. "schema/get_user_response"
+func (g *Generator) ModulePath(module *generator.Module) string {
+	segments := strings.Split(g.Spec.ModulePath(module), "/")
+	for i, segment := range segments {
+		segments[i] = identifier(generator.Snake(segment), "module")
+	}
+	return strings.Join(segments, "/")
+}
+
+// ClassName returns the Python name of a named type
+func ClassName(named *generator.NamedType) string {
+	if unicode.IsDigit(rune(named.Name[0])) {
+		return "_" + named.Name
+	}
+	return named.Name
+}
+
+// AttributeName returns the snake_case attribute of a JSON property
+func AttributeName(name string) string {
+	attribute := identifier(generator.Snake(name), "field")
+	if shadowing[attribute] {
+		attribute += "_"
+	}
+	if strings.HasPrefix(attribute, "model_") {
+		// reserved by pydantic
+		attribute = "field_" + attribute
+	}
+	return attribute
+}
+
+func identifier(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	if unicode.IsDigit(rune(name[0])) {
+		name = fallback + "_" + name
+	}
+	if keywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// imports collects "from x import y" statements of a module
+type imports map[string]map[string]bool
+
+func (is imports) add(from, name string) {
+	if is[from] == nil {
+		is[from] = make(map[string]bool)
+	}
+	is[from][name] = true
+}
+
+func (is imports) write(b *bytes.Buffer, froms []string) {
+	for _, from := range froms {
+		names := make([]string, 0, len(is[from]))
+		for name := range is[from] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(b, "from %s import %s\n", from, strings.Join(names, ", "))
+	}
+}
+
+func (is imports) sortedFroms(filter func(string) bool) []string {
+	froms := []string{}
+	for from := range is {
+		if filter(from) {
+			froms = append(froms, from)
+		}
+	}
+	sort.Strings(froms)
+	return froms
+}
+
+// relativeImport returns the relative import path of module `to` from module `from`
+func (g *Generator) relativeImport(from, to *generator.Module) string {
+	fromDirs := strings.Split(path.Dir(g.ModulePath(from)), "/")
+	target := strings.Split(g.ModulePath(to), "/")
+
+	common := 0
+	for common < len(fromDirs) && common < len(target)-1 && fromDirs[common] == target[common] {
+		common++
+	}
+	return strings.Repeat(".", 1+len(fromDirs)-common) + strings.Join(target[common:], ".")
+}
+
+// TypeExpr returns the Python annotation of a type
+func (g *Generator) TypeExpr(ref *generator.TypeRef, from *generator.Module, is imports) string {
+	expr := g.baseExpr(ref, from, is)
+	if ref.Nullable {
+		is.add("typing", "Optional")
+		return "Optional[" + expr + "]"
+	}
+	return expr
+}
+
+func (g *Generator) baseExpr(ref *generator.TypeRef, from *generator.Module, is imports) string {
+	if ref.Named != nil {
+		name := ClassName(ref.Named)
+		if ref.Named.Module != from {
+			is.add(g.relativeImport(from, ref.Named.Module), name)
+		}
+		return name
+	}
+
+	switch ref.Type {
+	case constants.STRING_TYPE:
+		switch ref.Format {
+		case constants.FORMAT_DATE_TIME:
+			is.add("datetime", "datetime")
+			return "datetime"
+		case constants.FORMAT_DATE:
+			is.add("datetime", "date")
+			return "date"
+		case constants.FORMAT_UUID:
+			is.add("uuid", "UUID")
+			return "UUID"
+		case constants.FORMAT_EMAIL:
+			is.add("pydantic", "EmailStr")
+			return "EmailStr"
+		case constants.FORMAT_BYTE, constants.FORMAT_BINARY:
+			return "bytes"
+		}
+		return "str"
+	case constants.INTEGER_TYPE:
+		return "int"
+	case constants.NUMBER_TYPE:
+		return "float"
+	case constants.BOOLEAN_TYPE:
+		return "bool"
+	case constants.ARRAY_TYPE:
+		is.add("typing", "List")
+		if ref.Items == nil {
+			is.add("typing", "Any")
+			return "List[Any]"
+		}
+		return "List[" + g.TypeExpr(ref.Items, from, is) + "]"
+	case constants.OBJECT_TYPE:
+		is.add("typing", "Any")
+		is.add("typing", "Dict")
+		return "Dict[str, Any]"
+	}
+	is.add("typing", "Any")
+	return "Any"
+}
+
+// Generate returns one module per model or schema file and the __init__.py of every package
+func (g *Generator) Generate() ([]*generator.File, error) {
+	files := []*generator.File{}
+	packages := map[string]bool{INIT_FILE: true}
+	for _, module := range g.Spec.Modules {
+		modulePath := g.ModulePath(module)
+		files = append(files, &generator.File{
+			Path: modulePath + FILE_EXT,
+			Data: g.generateModule(module),
+		})
+		for dir := path.Dir(modulePath); dir != "."; dir = path.Dir(dir) {
+			packages[dir+"/"+INIT_FILE] = true
+		}
+	}
+
+	inits := make([]string, 0, len(packages))
+	for init := range packages {
+		inits = append(inits, init)
+	}
+	sort.Strings(inits)
+	for _, init := range inits {
+		files = append(files, &generator.File{Path: init, Data: []byte(HEADER + "\n")})
+	}
+	return files, nil
+}
+
+func (g *Generator) generateModule(module *generator.Module) []byte {
+	is := imports{}
+	var body bytes.Buffer
+	for _, named := range generator.DependencyOrder(module) {
+		body.WriteString("\n\n")
+		g.writeType(&body, named, is)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(HEADER + "\n")
+	fmt.Fprintf(&b, "# Source: %s\n\n", filepath.ToSlash(module.Document.File))
+	b.WriteString("from __future__ import annotations\n")
+
+	standard := is.sortedFroms(func(from string) bool { return from != "pydantic" && !strings.HasPrefix(from, ".") })
+	if len(standard) > 0 {
+		b.WriteString("\n")
+		is.write(&b, standard)
+	}
+	if len(is["pydantic"]) > 0 {
+		b.WriteString("\n")
+		is.write(&b, []string{"pydantic"})
+	}
+	if local := is.sortedFroms(func(from string) bool { return strings.HasPrefix(from, ".") }); len(local) > 0 {
+		b.WriteString("\n")
+		is.write(&b, local)
+	}
+
+	b.Write(body.Bytes())
+	return b.Bytes()
+}
+
+func writeDocstring(b *bytes.Buffer, text string) {
+	text = strings.ReplaceAll(strings.TrimSpace(text), `"""`, `\"\"\"`)
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s\"\"\"%s\"\"\"\n", INDENT, lines[0])
+		return
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", INDENT)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s%s\n", INDENT, strings.TrimRight(line, " \t\r"))
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", INDENT)
+}
+
+func (g *Generator) writeType(b *bytes.Buffer, named *generator.NamedType, is imports) {
+	name := ClassName(named)
+
+	switch named.Kind {
+	case generator.KIND_OBJECT:
+		is.add("pydantic", "BaseModel")
+		fmt.Fprintf(b, "class %s(BaseModel):\n", name)
+		if named.Description != "" {
+			writeDocstring(b, named.Description)
+			b.WriteString("\n")
+		}
+
+		lines := []string{}
+		aliased := false
+		used := map[string]bool{}
+		for _, field := range named.Fields {
+			attribute := AttributeName(field.Name)
+			for i := 2; used[attribute]; i++ {
+				attribute = AttributeName(field.Name) + "_" + strconv.Itoa(i)
+			}
+			used[attribute] = true
+
+			annotation := g.TypeExpr(field.Type, named.Module, is)
+			if !field.Required && !field.Type.Nullable {
+				is.add("typing", "Optional")
+				annotation = "Optional[" + annotation + "]"
+			}
+
+			args := []string{}
+			if !field.Required {
+				args = append(args, "default=None")
+			}
+			if attribute != field.Name {
+				args = append(args, "alias="+strconv.Quote(field.Name))
+				aliased = true
+			}
+			if field.Description != "" {
+				args = append(args, "description="+strconv.Quote(field.Description))
+			}
+
+			switch {
+			case len(args) == 0:
+				lines = append(lines, fmt.Sprintf("%s: %s", attribute, annotation))
+			case len(args) == 1 && args[0] == "default=None":
+				lines = append(lines, fmt.Sprintf("%s: %s = None", attribute, annotation))
+			default:
+				is.add("pydantic", "Field")
+				lines = append(lines, fmt.Sprintf("%s: %s = Field(%s)", attribute, annotation, strings.Join(args, ", ")))
+			}
+		}
+
+		if aliased {
+			is.add("pydantic", "ConfigDict")
+			fmt.Fprintf(b, "%smodel_config = ConfigDict(populate_by_name=True)\n\n", INDENT)
+		}
+		for _, line := range lines {
+			fmt.Fprintf(b, "%s%s\n", INDENT, line)
+		}
+	case generator.KIND_ENUM:
+		g.writeEnum(b, named, is)
+	case generator.KIND_ALIAS:
+		if named.Alias.Named != nil && !named.Alias.Nullable {
+			fmt.Fprintf(b, "%s = %s\n", name, g.TypeExpr(named.Alias, named.Module, is))
+			return
+		}
+		is.add("pydantic", "RootModel")
+		fmt.Fprintf(b, "class %s(RootModel[%s]):\n", name, g.TypeExpr(named.Alias, named.Module, is))
+		if named.Description != "" {
+			writeDocstring(b, named.Description)
+		} else {
+			fmt.Fprintf(b, "%spass\n", INDENT)
+		}
+	}
+}
+
+func (g *Generator) writeEnum(b *bytes.Buffer, named *generator.NamedType, is imports) {
+	name := ClassName(named)
+	base := ""
+	switch named.EnumType {
+	case constants.STRING_TYPE:
+		base = "str"
+	case constants.INTEGER_TYPE:
+		base = "int"
+	default:
+		values := make([]string, 0, len(named.Enum))
+		for _, value := range named.Enum {
+			values = append(values, literal(value, named.EnumType))
+		}
+		is.add("typing", "Literal")
+		fmt.Fprintf(b, "%s = Literal[%s]\n", name, strings.Join(values, ", "))
+		return
+	}
+
+	is.add("enum", "Enum")
+	fmt.Fprintf(b, "class %s(%s, Enum):\n", name, base)
+	if named.Description != "" {
+		writeDocstring(b, named.Description)
+		b.WriteString("\n")
+	}
+	used := map[string]bool{}
+	for _, value := range named.Enum {
+		member := memberName(value)
+		for i := 2; used[member]; i++ {
+			member = memberName(value) + "_" + strconv.Itoa(i)
+		}
+		used[member] = true
+		fmt.Fprintf(b, "%s%s = %s\n", INDENT, member, literal(value, named.EnumType))
+	}
+}
+
+func memberName(value interface{}) string {
+	text := fmt.Sprint(value)
+	name := strings.ToUpper(generator.Snake(text))
+	if strings.HasPrefix(text, "-") {
+		name = "MINUS_" + name
+	}
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "VALUE_" + name
+	}
+	return strings.TrimSuffix(name, "_")
+}
+
+func literal(value interface{}, enumType string) string {
+	switch enumType {
+	case constants.INTEGER_TYPE:
+		if v, ok := value.(float64); ok {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return fmt.Sprint(value)
+	case constants.NUMBER_TYPE:
+		return fmt.Sprint(value)
+	case constants.BOOLEAN_TYPE:
+		if value == true {
+			return "True"
+		}
+		return "False"
+	}
+	return strconv.Quote(fmt.Sprint(value))
+}
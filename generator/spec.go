@@ -0,0 +1,388 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+// anchor gives the name of the type at Target; types below it are named
+// after it followed by the property names leading to them
+type anchor struct {
+	Target loader.Target
+	Parts  []string
+}
+
+// Spec is the language independent view of every type defined by the project.
+// Code generators walk its modules and map each NamedType and TypeRef to their language.
+type Spec struct {
+	Project *loader.Project
+	Modules []*Module
+
+	anchors   []*anchor
+	types     map[loader.Target]*NamedType
+	names     map[string]*NamedType
+	modules   map[string]*Module
+	resolving map[loader.Target]bool
+}
+
+// Build names every model and schema root and the objects and enums below them
+func Build(project *loader.Project) (*Spec, error) {
+	s := &Spec{
+		Project:   project,
+		types:     make(map[loader.Target]*NamedType),
+		names:     make(map[string]*NamedType),
+		modules:   make(map[string]*Module),
+		resolving: make(map[loader.Target]bool),
+	}
+
+	roots := project.Roots()
+	for _, root := range roots {
+		s.anchors = append(s.anchors, &anchor{Target: root.Target(), Parts: []string{rootName(root)}})
+	}
+
+	for _, root := range roots {
+		if _, err := s.define(root.Target(), root.Schema); err != nil {
+			return nil, err
+		}
+	}
+
+	s.sortModules()
+	return s, nil
+}
+
+func rootName(root *loader.Root) string {
+	if root.Name != "" {
+		return root.Name
+	}
+	if Pascal(root.Document.Title) != "" {
+		return root.Document.Title
+	}
+	base := filepath.Base(root.Document.File)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// Define names a schema outside of the model and schema files, e.g. an
+// inline request body of a path file. Objects below it are named after it.
+func (s *Spec) Define(name string, at loader.Target, schema *handler.Property) (*NamedType, error) {
+	if named, ok := s.types[at]; ok {
+		return named, nil
+	}
+	s.anchors = append(s.anchors, &anchor{Target: at, Parts: []string{name}})
+	named, err := s.define(at, schema)
+	s.sortModules()
+	return named, err
+}
+
+// Types returns every named type in module order
+func (s *Spec) Types() []*NamedType {
+	types := []*NamedType{}
+	for _, module := range s.Modules {
+		types = append(types, module.Types...)
+	}
+	return types
+}
+
+// Lookup returns the named type defined at the target
+func (s *Spec) Lookup(at loader.Target) (*NamedType, bool) {
+	named, ok := s.types[at]
+	return named, ok
+}
+
+// TypeOf returns the type of the schema located at `at`.
+// $refs are followed; objects with properties and enums become named types.
+func (s *Spec) TypeOf(at loader.Target, schema *handler.Property) (*TypeRef, error) {
+	if schema == nil {
+		return &TypeRef{}, nil
+	}
+
+	if schema.Ref != "" {
+		target, resolved, err := s.Project.ResolveRef(at.File, schema.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("%v (referenced from %s)", err, at)
+		}
+		if named, ok := s.types[target]; ok {
+			return &TypeRef{Named: named, Nullable: resolved.Nullable}, nil
+		}
+		if s.isRoot(target) || isNamed(resolved) {
+			named, err := s.define(target, resolved)
+			if err != nil {
+				return nil, err
+			}
+			return &TypeRef{Named: named, Nullable: resolved.Nullable}, nil
+		}
+		if s.resolving[target] {
+			return nil, fmt.Errorf("[ERROR] circular $ref: %s", target)
+		}
+		s.resolving[target] = true
+		defer delete(s.resolving, target)
+		return s.TypeOf(target, resolved)
+	}
+
+	if isNamed(schema) {
+		named, err := s.define(at, schema)
+		if err != nil {
+			return nil, err
+		}
+		return &TypeRef{Named: named, Nullable: schema.Nullable}, nil
+	}
+
+	return s.inlineType(at, schema)
+}
+
+func (s *Spec) inlineType(at loader.Target, schema *handler.Property) (*TypeRef, error) {
+	ref := &TypeRef{Type: schema.Type, Format: schema.Format, Nullable: schema.Nullable}
+	if schema.Format == constants.FORMAT_NONE {
+		ref.Format = ""
+	}
+	if schema.Type == constants.ARRAY_TYPE {
+		items, err := s.TypeOf(at.Child(fetcher.ITEMS_OPTION), schema.Items)
+		if err != nil {
+			return nil, err
+		}
+		ref.Items = items
+	}
+	return ref, nil
+}
+
+// isNamed reports whether a schema needs a definition of its own
+func isNamed(schema *handler.Property) bool {
+	return len(schema.Enum) > 0 || IsObject(schema) && len(schema.Properties) > 0
+}
+
+// IsObject reports whether a schema describes an object
+func IsObject(schema *handler.Property) bool {
+	return schema.Type == constants.OBJECT_TYPE || schema.Type == "" && len(schema.Properties) > 0
+}
+
+func (s *Spec) isRoot(at loader.Target) bool {
+	for _, a := range s.anchors {
+		if a.Target == at {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Spec) partsOf(at loader.Target) ([]string, error) {
+	var best *anchor
+	for _, a := range s.anchors {
+		if a.Target.Contains(at) && (best == nil || len(a.Target.Pointer) > len(best.Target.Pointer)) {
+			best = a
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("[ERROR] no name for the schema at %s", at)
+	}
+
+	parts := append([]string{}, best.Parts...)
+	tokens := loader.SplitPointer(strings.TrimPrefix(at.Pointer, best.Target.Pointer))
+	properties := strings.TrimPrefix(fetcher.PROPERTIES_PATH, "/")
+	for i := 0; i < len(tokens); i++ {
+		switch {
+		case tokens[i] == properties && i+1 < len(tokens):
+			i++
+			parts = append(parts, tokens[i])
+		case tokens[i] == fetcher.ITEMS_OPTION:
+			parts = append(parts, "Item")
+		default:
+			parts = append(parts, tokens[i])
+		}
+	}
+	return parts, nil
+}
+
+func (s *Spec) define(at loader.Target, schema *handler.Property) (*NamedType, error) {
+	if named, ok := s.types[at]; ok {
+		return named, nil
+	}
+
+	parts, err := s.partsOf(at)
+	if err != nil {
+		return nil, err
+	}
+	named := &NamedType{
+		Name:        Pascal(parts...),
+		Parts:       parts,
+		Location:    at,
+		Schema:      schema,
+		Description: schema.Description,
+		Module:      s.module(at.File),
+	}
+	if named.Name == "" {
+		return nil, fmt.Errorf("[ERROR] no name for the schema at %s", at)
+	}
+	if other, ok := s.names[named.Name]; ok {
+		return nil, fmt.Errorf("[ERROR] type name %s is used by both %s and %s", named.Name, other.Location, at)
+	}
+	s.types[at] = named
+	s.names[named.Name] = named
+	named.Module.Types = append(named.Module.Types, named)
+
+	switch {
+	case schema.Ref == "" && len(schema.Enum) > 0:
+		named.Kind = KIND_ENUM
+		named.Enum = schema.Enum
+		named.EnumType = schema.Type
+		if named.EnumType == "" {
+			named.EnumType = constants.STRING_TYPE
+		}
+	case schema.Ref == "" && IsObject(schema) && len(schema.Properties) > 0:
+		named.Kind = KIND_OBJECT
+		if err := s.defineFields(named); err != nil {
+			return nil, err
+		}
+	default:
+		named.Kind = KIND_ALIAS
+		if schema.Ref != "" {
+			named.Alias, err = s.TypeOf(at, schema)
+		} else {
+			// a root is never inlined, so describe it as if it were not named
+			named.Alias, err = s.inlineType(at, schema)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return named, nil
+}
+
+func (s *Spec) defineFields(named *NamedType) error {
+	schema := named.Schema
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop := schema.Properties[name]
+		if prop == nil {
+			prop = &handler.Property{}
+		}
+		at := named.Location.Child(strings.TrimPrefix(fetcher.PROPERTIES_PATH, "/"), name)
+
+		t, err := s.TypeOf(at, prop)
+		if err != nil {
+			return err
+		}
+		_, resolved, err := s.Project.Deref(at, prop)
+		if err != nil {
+			return err
+		}
+
+		description := prop.Description
+		if description == "" {
+			description = resolved.Description
+		}
+		named.Fields = append(named.Fields, &Field{
+			Name:        name,
+			Type:        t,
+			Required:    slices.Contains(schema.Required, name),
+			ReadOnly:    resolved.ReadOnly,
+			Description: description,
+			Schema:      resolved,
+		})
+	}
+	return nil
+}
+
+func (s *Spec) module(file string) *Module {
+	if module, ok := s.modules[file]; ok {
+		return module
+	}
+	module := &Module{Document: s.Project.Documents[file]}
+	if module.Document == nil {
+		module.Document = &loader.Document{File: file}
+	}
+	s.modules[file] = module
+	s.Modules = append(s.Modules, module)
+	return module
+}
+
+var kindOrder = map[string]int{loader.KIND_MODEL: 0, loader.KIND_SCHEMA: 1, loader.KIND_PATH: 2}
+
+// sortModules orders modules by kind and file, and types inside a module by
+// root and location so output does not depend on the order refs were found in
+func (s *Spec) sortModules() {
+	sort.SliceStable(s.Modules, func(i, j int) bool {
+		a, b := s.Modules[i].Document, s.Modules[j].Document
+		if kindOrder[a.Kind] != kindOrder[b.Kind] {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return a.File < b.File
+	})
+
+	for _, module := range s.Modules {
+		sort.SliceStable(module.Types, func(i, j int) bool {
+			a, b := module.Types[i].Location.Pointer, module.Types[j].Location.Pointer
+			if rootA, rootB := rootToken(a), rootToken(b); rootA != rootB {
+				return rootA < rootB
+			}
+			return a < b
+		})
+	}
+}
+
+func rootToken(pointer string) string {
+	tokens := loader.SplitPointer(pointer)
+	if len(tokens) == 0 {
+		return ""
+	}
+	return tokens[0]
+}
+
+// RelativeModulePath returns the path of a module's file relative to the
+// directory of its kind without extension, e.g. "model/user.yaml" -> "user"
+func (s *Spec) RelativeModulePath(module *Module) string {
+	return s.Project.RelativePath(module.Document)
+}
+
+// ModulePath returns the path of a module mirroring the project layout
+// without extension, e.g. "model/sub/address.yaml" -> "model/sub/address"
+func (s *Spec) ModulePath(module *Module) string {
+	return s.Project.LayoutPath(module.Document)
+}
+
+// RelativeImport returns the import path of module `to` from module `from`
+// in the ModulePath layout, e.g. "../model/user"
+func (s *Spec) RelativeImport(from, to *Module) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(s.ModulePath(from))), filepath.FromSlash(s.ModulePath(to)))
+	if err != nil {
+		return s.ModulePath(to)
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
+// WriteFiles writes generated files under outDir
+func WriteFiles(outDir string, files []*File) error {
+	if outDir == "" {
+		return errors.New("[ERROR] output directory is required")
+	}
+
+	for _, file := range files {
+		path := filepath.Join(outDir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, file.Data, 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("[INFO] Wrote %s\n", path)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package tmpl
+
+import (
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/generator"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+// Data is the root object every template is executed against.
+// $refs are resolved: a reference to a named type is a TypeRef whose Named
+// is that type, and a reference to a primitive is the primitive itself.
+type Data struct {
+	Models     []*Type      // named types of the model files
+	Schemas    []*Type      // named types of the schema files
+	Types      []*Type      // every named type, including the inline ones of operations
+	Modules    []*Module    // every file defining named types
+	Operations []*Operation // every operation of the path files, sorted by path and method
+}
+
+// Module is a model, schema or path file and the named types it defines
+type Module struct {
+	File  string // e.g. model/user.yaml
+	Kind  string // model, schema or path
+	Path  string // layout path without extension, e.g. model/sub/address
+	Types []*Type
+}
+
+// Type is a named type: a model or schema root, an object with properties or an enum
+type Type struct {
+	Name        string   // PascalCase name, e.g. OrderLinesItem
+	Parts       []string // words the name is built from, e.g. [Order lines Item]
+	Kind        string   // object, enum or alias
+	Module      string   // Path of the module defining the type
+	Ref         string   // location as <file>#<pointer>
+	Description string
+
+	Fields   []*Field      // object: properties sorted by name
+	Enum     []interface{} // enum: the values
+	EnumType string        // enum: JSON schema type of the values
+	Alias    *TypeRef      // alias: the aliased type
+}
+
+// Field is a property of an object type
+type Field struct {
+	Name        string // JSON name
+	Type        *TypeRef
+	Required    bool
+	ReadOnly    bool
+	Description string
+	Example     string
+}
+
+// TypeRef is the type of a field, parameter, body or array item. Either
+// Named is set, or Type, Format and Items describe an inline type.
+type TypeRef struct {
+	Named    *Type
+	Type     string // JSON schema type, "" when anything is allowed
+	Format   string
+	Items    *TypeRef // array items
+	Nullable bool
+}
+
+// Operation is a method of a path file
+type Operation struct {
+	Name        string // PascalCase name from the operationId, or the method and path
+	OperationID string
+	Method      string // upper case, e.g. GET
+	Path        string // URL path template, e.g. /users/{id}
+	File        string
+	Summary     string
+	Description string
+	Tags        []string
+	Parameters  []*Parameter
+	Body        *Body // nil without requestBody
+	Responses   []*Response
+}
+
+// Parameter is a path, query, header or cookie parameter
+type Parameter struct {
+	Name     string
+	In       string
+	Required bool
+	Type     *TypeRef
+}
+
+// Body is the request body in its preferred media type, JSON when offered
+type Body struct {
+	MediaType   string
+	Required    bool
+	Description string
+	Type        *TypeRef
+}
+
+// Response is a documented status code in its preferred media type
+type Response struct {
+	Code        string // e.g. 200, 4XX or default
+	Description string
+	MediaType   string   // "" without content
+	Type        *TypeRef // nil without content
+}
+
+// builder converts the spec into the template data, one Type per NamedType
+type builder struct {
+	types map[*generator.NamedType]*Type
+}
+
+// NewData builds the template data of a spec. Operations are loaded first so
+// their inline schemas are part of Types.
+func NewData(spec *generator.Spec) (*Data, error) {
+	operations, err := spec.Operations()
+	if err != nil {
+		return nil, err
+	}
+
+	b := &builder{types: make(map[*generator.NamedType]*Type)}
+	data := &Data{}
+	for _, module := range spec.Modules {
+		m := &Module{
+			File: module.Document.File,
+			Kind: module.Document.Kind,
+			Path: spec.ModulePath(module),
+		}
+		for _, named := range module.Types {
+			t := b.typeOf(named, m.Path)
+			m.Types = append(m.Types, t)
+			data.Types = append(data.Types, t)
+			switch m.Kind {
+			case loader.KIND_MODEL:
+				data.Models = append(data.Models, t)
+			case loader.KIND_SCHEMA:
+				data.Schemas = append(data.Schemas, t)
+			}
+		}
+		data.Modules = append(data.Modules, m)
+	}
+
+	// fill the types once every named type exists, so refs between modules resolve
+	for named, t := range b.types {
+		b.fill(named, t)
+	}
+
+	for _, op := range operations {
+		data.Operations = append(data.Operations, b.operation(op))
+	}
+	return data, nil
+}
+
+func (b *builder) typeOf(named *generator.NamedType, module string) *Type {
+	t := &Type{
+		Name:        named.Name,
+		Parts:       named.Parts,
+		Kind:        named.Kind,
+		Module:      module,
+		Ref:         named.Location.String(),
+		Description: named.Description,
+		Enum:        named.Enum,
+		EnumType:    named.EnumType,
+	}
+	b.types[named] = t
+	return t
+}
+
+func (b *builder) fill(named *generator.NamedType, t *Type) {
+	for _, field := range named.Fields {
+		f := &Field{
+			Name:        field.Name,
+			Type:        b.ref(field.Type),
+			Required:    field.Required,
+			ReadOnly:    field.ReadOnly,
+			Description: field.Description,
+		}
+		if field.Schema != nil {
+			f.Example = field.Schema.Example
+		}
+		t.Fields = append(t.Fields, f)
+	}
+	if named.Alias != nil {
+		t.Alias = b.ref(named.Alias)
+	}
+}
+
+func (b *builder) ref(ref *generator.TypeRef) *TypeRef {
+	if ref == nil {
+		return nil
+	}
+	t := &TypeRef{
+		Type:     ref.Type,
+		Format:   ref.Format,
+		Items:    b.ref(ref.Items),
+		Nullable: ref.Nullable,
+	}
+	if ref.Named != nil {
+		t.Named = b.types[ref.Named]
+	}
+	return t
+}
+
+func (b *builder) operation(op *generator.Operation) *Operation {
+	o := &Operation{
+		Name:        op.Name,
+		OperationID: op.API.OperationID,
+		Method:      strings.ToUpper(op.Method),
+		Path:        op.Path,
+		File:        op.Document.File,
+		Summary:     op.API.Summary,
+		Description: op.API.Description,
+		Tags:        op.API.Tags,
+	}
+	for _, param := range op.Parameters {
+		o.Parameters = append(o.Parameters, &Parameter{
+			Name:     param.Name,
+			In:       param.In,
+			Required: param.Required,
+			Type:     b.ref(param.Type),
+		})
+	}
+	if op.Body != nil {
+		o.Body = &Body{
+			MediaType:   op.Body.MediaType,
+			Required:    op.Body.Required,
+			Description: op.Body.Description,
+			Type:        b.ref(op.Body.Type),
+		}
+	}
+	for _, response := range op.Responses {
+		o.Responses = append(o.Responses, &Response{
+			Code:        response.Code,
+			Description: response.Description,
+			MediaType:   response.MediaType,
+			Type:        b.ref(response.Type),
+		})
+	}
+	return o
+}
@@ -0,0 +1,130 @@
+package tmpl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Daaaai0809/swagen-v2/generator"
+)
+
+const (
+	TEMPLATE_EXT = ".tmpl"
+
+	// templates whose file name starts with the prefix only define shared
+	// blocks and produce no output
+	PARTIAL_PREFIX = "_"
+)
+
+// Funcs are the functions available to templates in addition to the text/template builtins
+var Funcs = template.FuncMap{
+	"pascal":     func(s ...string) string { return generator.Pascal(s...) },
+	"camel":      func(s ...string) string { return generator.Camel(s...) },
+	"snake":      func(s ...string) string { return generator.Snake(s...) },
+	"kebab":      func(s ...string) string { return strings.ReplaceAll(generator.Snake(s...), "_", "-") },
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"join":       func(sep string, s []string) string { return strings.Join(s, sep) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+	"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+	"quote":      func(v interface{}) string { return fmt.Sprintf("%q", fmt.Sprint(v)) },
+	"toJSON": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"lines": func(s string) []string {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil
+		}
+		return strings.Split(s, "\n")
+	},
+}
+
+// Generator executes user templates against the data of a spec
+type Generator struct {
+	Spec      *generator.Spec
+	Templates []string // template files
+}
+
+func NewGenerator(spec *generator.Spec, templates []string) *Generator {
+	return &Generator{
+		Spec:      spec,
+		Templates: templates,
+	}
+}
+
+// ExpandTemplates returns the files matched by glob patterns, sorted and without duplicates
+func ExpandTemplates(patterns []string) ([]string, error) {
+	seen := map[string]bool{}
+	files := []string{}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("[ERROR] invalid template pattern %s: %v", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("[ERROR] no template matches %s", pattern)
+		}
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// OutputName returns the file a template is rendered to, e.g. models.kt.tmpl -> models.kt
+func OutputName(templateFile string) string {
+	return strings.TrimSuffix(filepath.Base(templateFile), TEMPLATE_EXT)
+}
+
+// Generate parses every template into one set, so they can use each
+// other's blocks, and renders each template which is not a partial
+func (g *Generator) Generate() ([]*generator.File, error) {
+	if len(g.Templates) == 0 {
+		return nil, fmt.Errorf("[ERROR] no template given")
+	}
+
+	set, err := template.New("").Funcs(Funcs).Option("missingkey=error").ParseFiles(g.Templates...)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] failed to parse templates: %v", err)
+	}
+
+	data, err := NewData(g.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []*generator.File{}
+	outputs := map[string]string{}
+	for _, file := range g.Templates {
+		name := filepath.Base(file)
+		if strings.HasPrefix(name, PARTIAL_PREFIX) {
+			continue
+		}
+		output := OutputName(file)
+		if other, ok := outputs[output]; ok {
+			return nil, fmt.Errorf("[ERROR] templates %s and %s are both rendered to %s", other, file, output)
+		}
+		outputs[output] = file
+
+		var b bytes.Buffer
+		if err := set.ExecuteTemplate(&b, name, data); err != nil {
+			return nil, fmt.Errorf("[ERROR] failed to execute template %s: %v", file, err)
+		}
+		files = append(files, &generator.File{Path: output, Data: b.Bytes()})
+	}
+	return files, nil
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+const (
+	KIND_OBJECT = "object"
+	KIND_ENUM   = "enum"
+	KIND_ALIAS  = "alias"
+)
+
+// TypeRef is the type of a field, an array item or an alias.
+// Either Named is set, or Type/Format/Items describe an inline type.
+type TypeRef struct {
+	Named    *NamedType
+	Type     string // JSON schema type, "" when anything is allowed
+	Format   string
+	Items    *TypeRef
+	Nullable bool
+}
+
+// NamedType is a type which gets its own definition in generated code:
+// every model and schema root, objects with properties and enums
+type NamedType struct {
+	Name        string
+	Parts       []string // words the name is built from, for language specific casing
+	Kind        string
+	Module      *Module
+	Location    loader.Target
+	Schema      *handler.Property
+	Description string
+
+	Fields   []*Field      // KIND_OBJECT
+	Enum     []interface{} // KIND_ENUM
+	EnumType string        // KIND_ENUM, JSON schema type of the values
+	Alias    *TypeRef      // KIND_ALIAS
+}
+
+// Field is a property of an object type
+type Field struct {
+	Name        string // JSON name
+	Type        *TypeRef
+	Required    bool
+	ReadOnly    bool
+	Description string
+	Schema      *handler.Property // schema of the field with $refs followed
+}
+
+// Module is the set of named types defined by one file of the project
+type Module struct {
+	Document *loader.Document
+	Types    []*NamedType
+}
+
+// File is a generated file, Path is relative to the output directory
+type File struct {
+	Path string
+	Data []byte
+}
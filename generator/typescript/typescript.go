@@ -0,0 +1,208 @@
+package typescript
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/generator"
+)
+
+const (
+	FILE_EXT = ".ts"
+	HEADER   = "// Code generated by swagen-v2. DO NOT EDIT."
+)
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Generator emits TypeScript interfaces and type aliases, one module per
+// model or schema file laid out like the project directories
+type Generator struct {
+	Spec *generator.Spec
+}
+
+func NewGenerator(spec *generator.Spec) *Generator {
+	return &Generator{
+		Spec: spec,
+	}
+}
+
+// TypeName returns the TypeScript name of a named type
+func TypeName(named *generator.NamedType) string {
+	if identifierPattern.MatchString(named.Name) {
+		return named.Name
+	}
+	return "_" + named.Name
+}
+
+// PropertyName quotes a JSON property name when it is not a valid identifier
+func PropertyName(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// Imports collects the names a module uses from other modules
+type Imports map[*generator.Module]map[string]bool
+
+func (is Imports) Add(module *generator.Module, name string) {
+	if is[module] == nil {
+		is[module] = make(map[string]bool)
+	}
+	is[module][name] = true
+}
+
+// WriteImports writes one import statement per module, sorted by module path
+func (is Imports) WriteImports(b *bytes.Buffer, spec *generator.Spec, from *generator.Module, statement string) {
+	modules := make([]*generator.Module, 0, len(is))
+	for imported := range is {
+		modules = append(modules, imported)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return spec.ModulePath(modules[i]) < spec.ModulePath(modules[j])
+	})
+	for _, imported := range modules {
+		names := make([]string, 0, len(is[imported]))
+		for name := range is[imported] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(b, "%s { %s } from %q;\n", statement, strings.Join(names, ", "), spec.RelativeImport(from, imported))
+	}
+}
+
+// TypeExpr returns the TypeScript type expression of a type
+func (g *Generator) TypeExpr(ref *generator.TypeRef, from *generator.Module, imports Imports) string {
+	expr := g.baseExpr(ref, from, imports)
+	if ref.Nullable {
+		return expr + " | null"
+	}
+	return expr
+}
+
+func (g *Generator) baseExpr(ref *generator.TypeRef, from *generator.Module, imports Imports) string {
+	if ref.Named != nil {
+		if ref.Named.Module != from {
+			imports.Add(ref.Named.Module, TypeName(ref.Named))
+		}
+		return TypeName(ref.Named)
+	}
+
+	switch ref.Type {
+	case constants.STRING_TYPE:
+		return "string"
+	case constants.INTEGER_TYPE, constants.NUMBER_TYPE:
+		return "number"
+	case constants.BOOLEAN_TYPE:
+		return "boolean"
+	case constants.ARRAY_TYPE:
+		if ref.Items == nil {
+			return "unknown[]"
+		}
+		elem := g.TypeExpr(ref.Items, from, imports)
+		if ref.Items.Nullable {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case constants.OBJECT_TYPE:
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+// Generate returns one module per model or schema file
+func (g *Generator) Generate() ([]*generator.File, error) {
+	files := []*generator.File{}
+	for _, module := range g.Spec.Modules {
+		files = append(files, &generator.File{
+			Path: g.Spec.ModulePath(module) + FILE_EXT,
+			Data: g.generateModule(module),
+		})
+	}
+	return files, nil
+}
+
+func (g *Generator) generateModule(module *generator.Module) []byte {
+	imports := Imports{}
+	var body bytes.Buffer
+	for _, named := range module.Types {
+		body.WriteString("\n")
+		g.WriteType(&body, named, imports)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(HEADER + "\n")
+	fmt.Fprintf(&b, "// Source: %s\n", filepath.ToSlash(module.Document.File))
+	if len(imports) > 0 {
+		b.WriteString("\n")
+	}
+	imports.WriteImports(&b, g.Spec, module, "import type")
+
+	b.Write(body.Bytes())
+	return b.Bytes()
+}
+
+// WriteDoc writes text as a JSDoc comment
+func WriteDoc(b *bytes.Buffer, indent, text string) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s * %s\n", indent, strings.TrimRight(line, " \t\r"))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+// WriteType writes the interface or type alias of a named type
+func (g *Generator) WriteType(b *bytes.Buffer, named *generator.NamedType, imports Imports) {
+	name := TypeName(named)
+	if named.Description != "" {
+		WriteDoc(b, "", named.Description)
+	}
+
+	switch named.Kind {
+	case generator.KIND_OBJECT:
+		fmt.Fprintf(b, "export interface %s {\n", name)
+		for _, field := range named.Fields {
+			if field.Description != "" {
+				WriteDoc(b, "  ", field.Description)
+			}
+			modifier := ""
+			if field.ReadOnly {
+				modifier = "readonly "
+			}
+			optional := ""
+			if !field.Required {
+				optional = "?"
+			}
+			fmt.Fprintf(b, "  %s%s%s: %s;\n", modifier, PropertyName(field.Name), optional, g.TypeExpr(field.Type, named.Module, imports))
+		}
+		b.WriteString("}\n")
+	case generator.KIND_ENUM:
+		values := make([]string, 0, len(named.Enum))
+		for _, value := range named.Enum {
+			values = append(values, Literal(value, named.EnumType))
+		}
+		fmt.Fprintf(b, "export type %s = %s;\n", name, strings.Join(values, " | "))
+	case generator.KIND_ALIAS:
+		fmt.Fprintf(b, "export type %s = %s;\n", name, g.TypeExpr(named.Alias, named.Module, imports))
+	}
+}
+
+// Literal returns an enum value as a TypeScript literal
+func Literal(value interface{}, enumType string) string {
+	switch enumType {
+	case constants.INTEGER_TYPE, constants.NUMBER_TYPE, constants.BOOLEAN_TYPE:
+		return fmt.Sprint(value)
+	}
+	return strconv.Quote(fmt.Sprint(value))
+}
@@ -0,0 +1,202 @@
+package zod
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/generator"
+	"github.com/Daaaai0809/swagen-v2/generator/typescript"
+)
+
+const (
+	FILE_EXT      = ".ts"
+	HEADER        = "// Code generated by swagen-v2. DO NOT EDIT."
+	SCHEMA_SUFFIX = "Schema"
+)
+
+// stringFormats are the zod string checks of the formats zod understands
+var stringFormats = map[string]string{
+	constants.FORMAT_EMAIL:     ".email()",
+	constants.FORMAT_UUID:      ".uuid()",
+	constants.FORMAT_DATE_TIME: ".datetime({ offset: true })",
+	constants.FORMAT_DATE:      ".date()",
+	constants.FORMAT_URI:       ".url()",
+	constants.FORMAT_IPV4:      `.ip({ version: "v4" })`,
+	constants.FORMAT_IPV6:      `.ip({ version: "v6" })`,
+}
+
+// Generator emits Zod schemas and their inferred types, one module per
+// model or schema file laid out like the project directories
+type Generator struct {
+	Spec *generator.Spec
+
+	types *typescript.Generator
+}
+
+func NewGenerator(spec *generator.Spec) *Generator {
+	return &Generator{
+		Spec:  spec,
+		types: typescript.NewGenerator(spec),
+	}
+}
+
+// SchemaName returns the name of the Zod schema constant of a named type
+func SchemaName(named *generator.NamedType) string {
+	return typescript.TypeName(named) + SCHEMA_SUFFIX
+}
+
+// moduleState tracks what a module imports and which of its own schemas are defined already
+type moduleState struct {
+	module      *generator.Module
+	imports     typescript.Imports
+	typeImports typescript.Imports
+	defined     map[*generator.NamedType]bool
+}
+
+func (ms *moduleState) reference(named *generator.NamedType) string {
+	name := SchemaName(named)
+	if named.Module != ms.module {
+		ms.imports.Add(named.Module, name)
+		return name
+	}
+	if !ms.defined[named] {
+		// only reached through a cycle, defer the lookup until parse time
+		return "z.lazy(() => " + name + ")"
+	}
+	return name
+}
+
+// Expr returns the Zod expression of a type
+func (g *Generator) Expr(ref *generator.TypeRef, ms *moduleState) string {
+	expr := g.baseExpr(ref, ms)
+	if ref.Nullable {
+		expr += ".nullable()"
+	}
+	return expr
+}
+
+func (g *Generator) baseExpr(ref *generator.TypeRef, ms *moduleState) string {
+	if ref.Named != nil {
+		return ms.reference(ref.Named)
+	}
+
+	switch ref.Type {
+	case constants.STRING_TYPE:
+		return "z.string()" + stringFormats[ref.Format]
+	case constants.INTEGER_TYPE:
+		return "z.number().int()"
+	case constants.NUMBER_TYPE:
+		return "z.number()"
+	case constants.BOOLEAN_TYPE:
+		return "z.boolean()"
+	case constants.ARRAY_TYPE:
+		if ref.Items == nil {
+			return "z.array(z.unknown())"
+		}
+		return "z.array(" + g.Expr(ref.Items, ms) + ")"
+	case constants.OBJECT_TYPE:
+		return "z.record(z.unknown())"
+	}
+	return "z.unknown()"
+}
+
+// Generate returns one module per model or schema file
+func (g *Generator) Generate() ([]*generator.File, error) {
+	files := []*generator.File{}
+	for _, module := range g.Spec.Modules {
+		files = append(files, &generator.File{
+			Path: g.Spec.ModulePath(module) + FILE_EXT,
+			Data: g.generateModule(module),
+		})
+	}
+	return files, nil
+}
+
+func (g *Generator) generateModule(module *generator.Module) []byte {
+	ms := &moduleState{
+		module:      module,
+		imports:     typescript.Imports{},
+		typeImports: typescript.Imports{},
+		defined:     make(map[*generator.NamedType]bool),
+	}
+
+	var body bytes.Buffer
+	for _, named := range generator.DependencyOrder(module) {
+		body.WriteString("\n")
+		g.writeSchema(&body, named, ms)
+		ms.defined[named] = true
+	}
+
+	var b bytes.Buffer
+	b.WriteString(HEADER + "\n")
+	fmt.Fprintf(&b, "// Source: %s\n\n", filepath.ToSlash(module.Document.File))
+	b.WriteString("import { z } from \"zod\";\n")
+	ms.imports.WriteImports(&b, g.Spec, module, "import")
+	ms.typeImports.WriteImports(&b, g.Spec, module, "import type")
+
+	b.Write(body.Bytes())
+	return b.Bytes()
+}
+
+// TypeScript cannot infer the type of a recursive schema, so those are
+// annotated with a written out type
+func (g *Generator) writeSchema(b *bytes.Buffer, named *generator.NamedType, ms *moduleState) {
+	name := SchemaName(named)
+	recursive := generator.IsRecursive(named)
+	if recursive {
+		g.types.WriteType(b, named, ms.typeImports)
+	}
+	if named.Description != "" {
+		typescript.WriteDoc(b, "", named.Description)
+	}
+
+	declaration := "export const " + name
+	if recursive {
+		declaration += ": z.ZodType<" + typescript.TypeName(named) + ">"
+	}
+
+	switch named.Kind {
+	case generator.KIND_OBJECT:
+		fmt.Fprintf(b, "%s = z.object({\n", declaration)
+		for _, field := range named.Fields {
+			if field.Description != "" {
+				typescript.WriteDoc(b, "  ", field.Description)
+			}
+			expr := g.Expr(field.Type, ms)
+			if !field.Required {
+				expr += ".optional()"
+			}
+			fmt.Fprintf(b, "  %s: %s,\n", typescript.PropertyName(field.Name), expr)
+		}
+		b.WriteString("});\n")
+	case generator.KIND_ENUM:
+		fmt.Fprintf(b, "%s = %s;\n", declaration, enumExpr(named))
+	case generator.KIND_ALIAS:
+		fmt.Fprintf(b, "%s = %s;\n", declaration, g.Expr(named.Alias, ms))
+	}
+	if !recursive {
+		fmt.Fprintf(b, "export type %s = z.infer<typeof %s>;\n", typescript.TypeName(named), name)
+	}
+}
+
+func enumExpr(named *generator.NamedType) string {
+	values := make([]string, 0, len(named.Enum))
+	for _, value := range named.Enum {
+		values = append(values, typescript.Literal(value, named.EnumType))
+	}
+
+	if named.EnumType == constants.STRING_TYPE {
+		return "z.enum([" + strings.Join(values, ", ") + "])"
+	}
+	if len(values) == 1 {
+		return "z.literal(" + values[0] + ")"
+	}
+	literals := make([]string, 0, len(values))
+	for _, value := range values {
+		literals = append(literals, "z.literal("+value+")")
+	}
+	return "z.union([" + strings.Join(literals, ", ") + "])"
+}
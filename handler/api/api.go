@@ -55,7 +55,7 @@ func (ah *APIHandler) HandleGenerateAPICommand() error {
 	}
 
 	var fileName string
-	if err := ah.Input.StringInput(&fileName, "Enter the API file name (without extension)", ah.APIValidator.Validator_Alphanumeric_Underscore()); err != nil {
+	if err := ah.Input.StringInput(&fileName, "Enter the API file name (without extension), the URL path segment or a path parameter like {id}", ah.APIValidator.Validator_Path_Segment()); err != nil {
 		return err
 	}
 
@@ -23,6 +23,7 @@ type API struct {
 	Summary     string               `yaml:"summary,omitempty"`
 	Description string               `yaml:"description,omitempty"`
 	Tags        []string             `yaml:"tags,omitempty"`
+	Deprecated  bool                 `yaml:"deprecated,omitempty"`
 	Parameters  []*Parameter         `yaml:"parameters,omitempty"`
 	RequestBody *RequestBody         `yaml:"requestBody,omitempty"`
 	Responses   map[string]*Response `yaml:"responses,omitempty"`
@@ -156,9 +157,11 @@ func (a *API) GenerateFile(fileName, method string) error {
 type Parameter struct {
 	Input input.IInputMethods `yaml:"-"`
 
-	In     string       `yaml:"in,omitempty"`
-	Name   string       `yaml:"name,omitempty"`
-	Schema *ParamSchema `yaml:"schema,omitempty"`
+	In         string       `yaml:"in,omitempty"`
+	Name       string       `yaml:"name,omitempty"`
+	Required   bool         `yaml:"required,omitempty"`
+	Deprecated bool         `yaml:"deprecated,omitempty"`
+	Schema     *ParamSchema `yaml:"schema,omitempty"`
 }
 
 func NewParameter(input input.IInputMethods, name string, fileFetcher fetcher.IFileFetcher, directoryPath string) *Parameter {
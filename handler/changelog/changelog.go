@@ -0,0 +1,75 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Daaaai0809/swagen-v2/diff"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+const DEFAULT_TO = "HEAD"
+
+type ChangelogHandler struct {
+	OutputPath string // Markdown file, "" writes to stdout
+	JSONPath   string // JSON file, "" writes none
+}
+
+func NewChangelogHandler(outputPath, jsonPath string) *ChangelogHandler {
+	return &ChangelogHandler{
+		OutputPath: outputPath,
+		JSONPath:   jsonPath,
+	}
+}
+
+// HandleChangelogCommand writes the changelog between two versions of the
+// spec, each a git revision or a directory
+func (ch *ChangelogHandler) HandleChangelogCommand(from, to string) error {
+	oldProject, err := loader.LoadSource(from)
+	if err != nil {
+		return err
+	}
+	newProject, err := loader.LoadSource(to)
+	if err != nil {
+		return err
+	}
+
+	report, err := diff.Compare(oldProject, newProject)
+	if err != nil {
+		return err
+	}
+	changelog := diff.NewChangelog(report, from, to)
+
+	if ch.OutputPath == "" {
+		if _, err := os.Stdout.Write(changelog.Markdown()); err != nil {
+			return err
+		}
+	} else if err := writeFile(ch.OutputPath, changelog.Markdown()); err != nil {
+		return err
+	}
+
+	if ch.JSONPath != "" {
+		data, err := json.MarshalIndent(changelog, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeFile(ch.JSONPath, append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	// the Markdown may go to stdout, so status messages go to stderr
+	fmt.Fprintf(os.Stderr, "[INFO] Wrote %s\n", path)
+	return nil
+}
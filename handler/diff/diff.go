@@ -0,0 +1,83 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Daaaai0809/swagen-v2/diff"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+const (
+	FORMAT_TEXT = "text"
+	FORMAT_JSON = "json"
+
+	// DEFAULT_NEW_SOURCE compares against the working tree
+	DEFAULT_NEW_SOURCE = "."
+)
+
+type DiffHandler struct {
+	Format string
+}
+
+func NewDiffHandler(format string) *DiffHandler {
+	return &DiffHandler{
+		Format: format,
+	}
+}
+
+// HandleDiffCommand compares the operations of two spec trees, each a
+// directory or a git revision. It returns an error when any change is
+// breaking, so the command fails in CI.
+func (dh *DiffHandler) HandleDiffCommand(oldSource, newSource string) error {
+	if dh.Format != FORMAT_TEXT && dh.Format != FORMAT_JSON {
+		return fmt.Errorf("[ERROR] unknown format %q: use %s or %s", dh.Format, FORMAT_TEXT, FORMAT_JSON)
+	}
+
+	oldProject, err := loader.LoadSource(oldSource)
+	if err != nil {
+		return err
+	}
+	newProject, err := loader.LoadSource(newSource)
+	if err != nil {
+		return err
+	}
+
+	report, err := diff.Compare(oldProject, newProject)
+	if err != nil {
+		return err
+	}
+
+	if dh.Format == FORMAT_JSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printReport(report)
+	}
+
+	if report.Breaking > 0 {
+		return fmt.Errorf("[ERROR] %d breaking change(s) between %s and %s", report.Breaking, oldSource, newSource)
+	}
+	return nil
+}
+
+func printReport(report *diff.Report) {
+	if len(report.Changes) == 0 {
+		fmt.Println("[INFO] No changes.")
+		return
+	}
+
+	for _, change := range report.Changes {
+		label := "[INFO]"
+		if change.Breaking {
+			label = "[BREAKING]"
+		}
+		fmt.Printf("%s %s\n", label, change)
+	}
+	fmt.Printf("[INFO] %d change(s), %d breaking.\n", len(report.Changes), report.Breaking)
+}
@@ -0,0 +1,42 @@
+package docs
+
+import (
+	"github.com/Daaaai0809/swagen-v2/generator"
+	"github.com/Daaaai0809/swagen-v2/generator/docs"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+const (
+	FORMAT_HTML     = docs.FORMAT_HTML
+	FORMAT_MARKDOWN = docs.FORMAT_MARKDOWN
+)
+
+type DocsHandler struct {
+	OutputPath string
+}
+
+func NewDocsHandler(outputPath string) *DocsHandler {
+	return &DocsHandler{
+		OutputPath: outputPath,
+	}
+}
+
+// HandleDocsCommand writes the API reference of every operation and named
+// type to the output directory
+func (dh *DocsHandler) HandleDocsCommand(format, title string) error {
+	project, err := loader.LoadFromEnv()
+	if err != nil {
+		return err
+	}
+	spec, err := generator.Build(project)
+	if err != nil {
+		return err
+	}
+
+	files, err := docs.NewGenerator(spec, format, title).Generate()
+	if err != nil {
+		return err
+	}
+
+	return generator.WriteFiles(dh.OutputPath, files)
+}
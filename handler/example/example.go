@@ -0,0 +1,109 @@
+package example
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Daaaai0809/swagen-v2/generator/example"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+// DEFAULT_SEED is the seed used when none is given
+const DEFAULT_SEED = example.DEFAULT_SEED
+
+type ExampleHandler struct {
+	Seed       int64
+	OutputPath string // "" writes to stdout
+}
+
+func NewExampleHandler(seed int64, outputPath string) *ExampleHandler {
+	return &ExampleHandler{
+		Seed:       seed,
+		OutputPath: outputPath,
+	}
+}
+
+// HandleExampleCommand writes a JSON instance of the schema at <file>#<pointer>.
+// A path file or an operation (api/users.yaml#/get) gives the examples of
+// its parameters, request body and responses.
+func (eh *ExampleHandler) HandleExampleCommand(arg string) error {
+	project, err := loader.LoadFromEnv()
+	if err != nil {
+		return err
+	}
+
+	value, err := eh.instance(project, loader.ParseTarget(arg))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if eh.OutputPath == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(eh.OutputPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(eh.OutputPath, data, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("[INFO] Wrote %s\n", eh.OutputPath)
+	return nil
+}
+
+func (eh *ExampleHandler) instance(project *loader.Project, target loader.Target) (interface{}, error) {
+	synthesizer := example.NewSynthesizer(project, eh.Seed)
+
+	doc, ok := project.Documents[target.File]
+	if !ok {
+		return nil, fmt.Errorf("[ERROR] file not found: %s", target.File)
+	}
+	if doc.Kind == loader.KIND_PATH && len(loader.SplitPointer(target.Pointer)) <= 1 {
+		return eh.operations(project, synthesizer, target)
+	}
+
+	schema, err := project.Lookup(target)
+	if err != nil {
+		return nil, err
+	}
+	return synthesizer.Instance(target, schema)
+}
+
+// operations returns the examples of the operations of a path file by method,
+// or of the single operation the target names
+func (eh *ExampleHandler) operations(project *loader.Project, synthesizer *example.Synthesizer, target loader.Target) (interface{}, error) {
+	operations, err := project.Operations()
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]interface{}{}
+	for _, op := range operations {
+		if op.Document.File != target.File {
+			continue
+		}
+		if target.Pointer != "" && op.Pointer() != target.Pointer {
+			continue
+		}
+		value, err := synthesizer.Operation(op)
+		if err != nil {
+			return nil, err
+		}
+		if target.Pointer != "" {
+			return value, nil
+		}
+		out[op.Method] = value
+	}
+	if target.Pointer != "" {
+		return nil, fmt.Errorf("[ERROR] operation not found: %s", target)
+	}
+	return out, nil
+}
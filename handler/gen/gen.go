@@ -0,0 +1,215 @@
+package gen
+
+import (
+	"path/filepath"
+
+	"github.com/Daaaai0809/swagen-v2/generator"
+	"github.com/Daaaai0809/swagen-v2/generator/golang"
+	"github.com/Daaaai0809/swagen-v2/generator/jsonschema"
+	"github.com/Daaaai0809/swagen-v2/generator/proto"
+	"github.com/Daaaai0809/swagen-v2/generator/python"
+	"github.com/Daaaai0809/swagen-v2/generator/tmpl"
+	"github.com/Daaaai0809/swagen-v2/generator/typescript"
+	"github.com/Daaaai0809/swagen-v2/generator/zod"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+type GenHandler struct {
+	OutputPath string
+}
+
+func NewGenHandler(outputPath string) *GenHandler {
+	return &GenHandler{
+		OutputPath: outputPath,
+	}
+}
+
+func (gh *GenHandler) loadSpec() (*generator.Spec, error) {
+	project, err := loader.LoadFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return generator.Build(project)
+}
+
+// HandleGoCommand writes Go types for every model and schema to the output directory
+func (gh *GenHandler) HandleGoCommand(packageName string) error {
+	spec, err := gh.loadSpec()
+	if err != nil {
+		return err
+	}
+
+	if packageName == "" {
+		packageName = golang.PackageName(gh.OutputPath)
+	} else if err := golang.CheckPackageName(packageName); err != nil {
+		return err
+	}
+
+	files, err := golang.NewGenerator(spec, packageName).Generate()
+	if err != nil {
+		return err
+	}
+
+	return generator.WriteFiles(gh.OutputPath, files)
+}
+
+// HandleServerCommand writes Go types and a net/http server stub of every
+// operation of the path files to the output directory
+func (gh *GenHandler) HandleServerCommand(packageName string) error {
+	spec, err := gh.loadSpec()
+	if err != nil {
+		return err
+	}
+
+	if packageName == "" {
+		packageName = golang.PackageName(gh.OutputPath)
+	} else if err := golang.CheckPackageName(packageName); err != nil {
+		return err
+	}
+
+	files, err := golang.NewGenerator(spec, packageName).GenerateServer()
+	if err != nil {
+		return err
+	}
+
+	return generator.WriteFiles(gh.OutputPath, files)
+}
+
+// HandleClientCommand writes Go types and an HTTP client of every operation
+// of the path files to the output directory
+func (gh *GenHandler) HandleClientCommand(packageName string) error {
+	spec, err := gh.loadSpec()
+	if err != nil {
+		return err
+	}
+
+	if packageName == "" {
+		packageName = golang.PackageName(gh.OutputPath)
+	} else if err := golang.CheckPackageName(packageName); err != nil {
+		return err
+	}
+
+	files, err := golang.NewGenerator(spec, packageName).GenerateClient()
+	if err != nil {
+		return err
+	}
+
+	return generator.WriteFiles(gh.OutputPath, files)
+}
+
+// HandleTSCommand writes TypeScript types for every model and schema to the output directory
+func (gh *GenHandler) HandleTSCommand() error {
+	spec, err := gh.loadSpec()
+	if err != nil {
+		return err
+	}
+
+	files, err := typescript.NewGenerator(spec).Generate()
+	if err != nil {
+		return err
+	}
+
+	return generator.WriteFiles(gh.OutputPath, files)
+}
+
+// HandleZodCommand writes Zod schemas for every model and schema to the output directory
+func (gh *GenHandler) HandleZodCommand() error {
+	spec, err := gh.loadSpec()
+	if err != nil {
+		return err
+	}
+
+	files, err := zod.NewGenerator(spec).Generate()
+	if err != nil {
+		return err
+	}
+
+	return generator.WriteFiles(gh.OutputPath, files)
+}
+
+// HandlePythonCommand writes pydantic models for every model and schema to the output directory
+func (gh *GenHandler) HandlePythonCommand() error {
+	spec, err := gh.loadSpec()
+	if err != nil {
+		return err
+	}
+
+	files, err := python.NewGenerator(spec).Generate()
+	if err != nil {
+		return err
+	}
+
+	return generator.WriteFiles(gh.OutputPath, files)
+}
+
+// HandleProtoCommand writes proto3 messages for every model and schema to the
+// output directory. Field numbers are kept in the lock file across runs.
+func (gh *GenHandler) HandleProtoCommand(packageName, lockPath string) error {
+	spec, err := gh.loadSpec()
+	if err != nil {
+		return err
+	}
+
+	if packageName == "" {
+		packageName = proto.DEFAULT_PACKAGE
+	}
+	if lockPath == "" {
+		lockPath = filepath.Join(gh.OutputPath, proto.LOCK_FILE_NAME)
+	}
+
+	lock, err := proto.LoadLock(lockPath)
+	if err != nil {
+		return err
+	}
+
+	files, err := proto.NewGenerator(spec, packageName, lock).Generate()
+	if err != nil {
+		return err
+	}
+
+	if err := generator.WriteFiles(gh.OutputPath, files); err != nil {
+		return err
+	}
+
+	return lock.Save(lockPath)
+}
+
+// HandleJSONSchemaCommand writes a JSON Schema document for every model and schema root to the output directory
+func (gh *GenHandler) HandleJSONSchemaCommand(baseURI string, dereference bool) error {
+	project, err := loader.LoadFromEnv()
+	if err != nil {
+		return err
+	}
+
+	if baseURI == "" {
+		baseURI = jsonschema.DEFAULT_BASE_URI
+	}
+
+	files, err := jsonschema.NewGenerator(project, baseURI, dereference).Generate()
+	if err != nil {
+		return err
+	}
+
+	return generator.WriteFiles(gh.OutputPath, files)
+}
+
+// HandleTemplateCommand renders user text/templates against the project data
+// model, one output file per template
+func (gh *GenHandler) HandleTemplateCommand(patterns []string) error {
+	templates, err := tmpl.ExpandTemplates(patterns)
+	if err != nil {
+		return err
+	}
+
+	spec, err := gh.loadSpec()
+	if err != nil {
+		return err
+	}
+
+	files, err := tmpl.NewGenerator(spec, templates).Generate()
+	if err != nil {
+		return err
+	}
+
+	return generator.WriteFiles(gh.OutputPath, files)
+}
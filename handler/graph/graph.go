@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Daaaai0809/swagen-v2/generator/graph"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+const (
+	FORMAT_DOT       = graph.FORMAT_DOT
+	GRANULARITY_FILE = graph.GRANULARITY_FILE
+)
+
+type GraphHandler struct {
+	OutputPath string // "" writes to stdout
+}
+
+func NewGraphHandler(outputPath string) *GraphHandler {
+	return &GraphHandler{
+		OutputPath: outputPath,
+	}
+}
+
+// HandleGraphCommand renders the $ref graph of every model, schema and path
+// file, optionally limited to what is reachable from or reaches a file
+func (gh *GraphHandler) HandleGraphCommand(format, granularity, from, to string) error {
+	project, err := loader.LoadFromEnv()
+	if err != nil {
+		return err
+	}
+
+	g, err := graph.Build(project, granularity, from, to)
+	if err != nil {
+		return err
+	}
+	data, err := g.Render(format)
+	if err != nil {
+		return err
+	}
+
+	if gh.OutputPath == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(gh.OutputPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(gh.OutputPath, data, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("[INFO] Wrote %s\n", gh.OutputPath)
+	return nil
+}
@@ -0,0 +1,48 @@
+package mock
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+type MockHandler struct {
+	Port int
+	Seed int64
+}
+
+func NewMockHandler(port int, seed int64) *MockHandler {
+	return &MockHandler{
+		Port: port,
+		Seed: seed,
+	}
+}
+
+// HandleMockCommand serves every operation of the path files on the port
+// until the server stops
+func (mh *MockHandler) HandleMockCommand() error {
+	project, err := loader.LoadFromEnv()
+	if err != nil {
+		return err
+	}
+
+	server, err := NewServer(project, mh.Seed)
+	if err != nil {
+		return err
+	}
+	if len(server.Operations) == 0 {
+		fmt.Printf("[WARN] No operations found under %s\n", project.APIRoot)
+	}
+	for _, op := range server.Operations {
+		fmt.Printf("[INFO] %-7s %s (%s)\n", strings.ToUpper(op.Method), op.Path, op.Document.File)
+	}
+
+	addr := fmt.Sprintf(":%d", mh.Port)
+	fmt.Printf("[INFO] Mock server listening on http://localhost%s\n", addr)
+	if err := http.ListenAndServe(addr, server); err != nil {
+		return fmt.Errorf("[ERROR] mock server stopped: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/generator"
+	"github.com/Daaaai0809/swagen-v2/generator/example"
+	"github.com/Daaaai0809/swagen-v2/handler/api"
+	"github.com/Daaaai0809/swagen-v2/loader"
+	"github.com/Daaaai0809/swagen-v2/validator/contract"
+)
+
+const (
+	PREFER_HEADER       = "Prefer"
+	PREFER_CODE         = "code"
+	DEFAULT_STATUS_CODE = http.StatusOK
+)
+
+// Server serves every operation of the project with synthesized responses
+type Server struct {
+	Project     *loader.Project
+	Operations  []*loader.Operation
+	Synthesizer *example.Synthesizer
+	Checker     *contract.Checker
+}
+
+func NewServer(project *loader.Project, seed int64) (*Server, error) {
+	checker, err := contract.NewChecker(project)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		Project:     project,
+		Operations:  checker.Operations,
+		Synthesizer: example.NewSynthesizer(project, seed),
+		Checker:     checker,
+	}, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the mock is called from frontends served on other origins
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	op, _, pathMatched := loader.MatchOperation(s.Operations, r.Method, r.URL.Path)
+	if op == nil {
+		switch {
+		case pathMatched && r.Method == http.MethodOptions:
+			s.preflight(w, r)
+		case pathMatched:
+			s.problem(w, r, &contract.Problem{Status: http.StatusMethodNotAllowed, Detail: fmt.Sprintf("%s is not documented for %s", r.Method, r.URL.Path)})
+		default:
+			s.problem(w, r, &contract.Problem{Status: http.StatusNotFound, Detail: fmt.Sprintf("no path file matches %s", r.URL.Path)})
+		}
+		return
+	}
+
+	if problem := s.validateRequest(op, r); problem != nil {
+		s.problem(w, r, problem)
+		return
+	}
+
+	code, response, err := SelectResponse(op, PreferredCode(r.Header.Get(PREFER_HEADER)))
+	if err != nil {
+		s.problem(w, r, &contract.Problem{Status: http.StatusBadRequest, Detail: err.Error()})
+		return
+	}
+
+	status := StatusOf(code, r.Header.Get(PREFER_HEADER))
+	contentType, body, err := s.responseBody(op, code, response)
+	if err != nil {
+		s.problem(w, r, &contract.Problem{Status: http.StatusInternalServerError, Detail: err.Error()})
+		return
+	}
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.WriteHeader(status)
+	w.Write(body)
+	fmt.Printf("[INFO] %s %s -> %d\n", r.Method, r.URL.Path, status)
+}
+
+func (s *Server) preflight(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS")
+	if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+		w.Header().Set("Access-Control-Allow-Headers", headers)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) problem(w http.ResponseWriter, r *http.Request, problem *contract.Problem) {
+	problem.Write(w)
+	fmt.Printf("[INFO] %s %s -> %d %s\n", r.Method, r.URL.Path, problem.Status, problem.Detail)
+}
+
+// validateRequest checks the request body against the requestBody schema of its media type
+func (s *Server) validateRequest(op *loader.Operation, r *http.Request) *contract.Problem {
+	if op.API.RequestBody == nil {
+		return nil
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return &contract.Problem{Status: http.StatusBadRequest, Detail: err.Error()}
+	}
+
+	violations := s.Checker.CheckRequestBody(op, r.Header.Get("Content-Type"), data)
+	if len(violations) == 0 {
+		return nil
+	}
+	if status := contract.ProblemStatus(violations); status != http.StatusBadRequest {
+		return &contract.Problem{Status: status, Detail: violations[0].Message}
+	}
+	return &contract.Problem{
+		Status:     http.StatusBadRequest,
+		Title:      "Request body does not match the schema",
+		Detail:     fmt.Sprintf("%d schema violation(s)", len(violations)),
+		Violations: violations,
+	}
+}
+
+// responseBody synthesizes the body of a response in its preferred media type
+func (s *Server) responseBody(op *loader.Operation, code string, response *api.Response) (string, []byte, error) {
+	if response == nil {
+		return "", nil, nil
+	}
+	mediaType := generator.PreferredMediaType(response.Content)
+	if mediaType == "" {
+		return "", nil, nil
+	}
+	content := response.Content[mediaType]
+	if content == nil || content.Schema == nil {
+		return mediaType, nil, nil
+	}
+
+	at := loader.Target{File: op.Document.File, Pointer: op.Pointer()}.Child("responses", code, "content", mediaType, "schema")
+	value, err := s.Synthesizer.Instance(at, content.Schema)
+	if err != nil {
+		return "", nil, err
+	}
+	if text, ok := value.(string); ok && !generator.IsJSONMediaType(mediaType) {
+		return mediaType, []byte(text), nil
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", nil, err
+	}
+	return mediaType, append(data, '\n'), nil
+}
+
+// PreferredCode returns the code of a `Prefer: code=404` header, "" without one
+func PreferredCode(prefer string) string {
+	for _, preference := range strings.FieldsFunc(prefer, func(r rune) bool { return r == ',' || r == ';' }) {
+		name, value, ok := strings.Cut(strings.TrimSpace(preference), "=")
+		if ok && strings.EqualFold(strings.TrimSpace(name), PREFER_CODE) {
+			return strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return ""
+}
+
+// SelectResponse returns the documented response key and response to serve.
+// A preferred code matches its exact key, then its range like 4XX, then
+// default. Without one the lowest 2XX code is served, else the first one.
+func SelectResponse(op *loader.Operation, preferred string) (string, *api.Response, error) {
+	responses := op.API.Responses
+	if preferred != "" {
+		candidates := []string{preferred}
+		if generator.IsFixedStatus(preferred) {
+			candidates = append(candidates, preferred[:1]+"XX", preferred[:1]+"xx")
+		}
+		candidates = append(candidates, generator.DEFAULT_RESPONSE_CODE)
+		for _, candidate := range candidates {
+			if response, ok := responses[candidate]; ok {
+				return candidate, response, nil
+			}
+		}
+		return "", nil, fmt.Errorf("status %s is not documented for %s %s", preferred, strings.ToUpper(op.Method), op.Path)
+	}
+
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		// fixed codes first, default last
+		if generator.IsFixedStatus(codes[i]) != generator.IsFixedStatus(codes[j]) {
+			return generator.IsFixedStatus(codes[i])
+		}
+		if (codes[i] == generator.DEFAULT_RESPONSE_CODE) != (codes[j] == generator.DEFAULT_RESPONSE_CODE) {
+			return codes[j] == generator.DEFAULT_RESPONSE_CODE
+		}
+		return codes[i] < codes[j]
+	})
+	for _, code := range codes {
+		if strings.HasPrefix(code, "2") {
+			return code, responses[code], nil
+		}
+	}
+	if len(codes) > 0 {
+		return codes[0], responses[codes[0]], nil
+	}
+	return "", nil, nil
+}
+
+// StatusOf returns the status code served for a response key: the key
+// itself, the preferred code for ranges and default, or their lowest code
+func StatusOf(code, prefer string) int {
+	if generator.IsFixedStatus(code) {
+		status, _ := strconv.Atoi(code)
+		return status
+	}
+	if preferred, err := strconv.Atoi(PreferredCode(prefer)); err == nil && preferred >= 100 && preferred <= 599 {
+		return preferred
+	}
+	if class, ok := generator.StatusClass(code); ok {
+		return class * 100
+	}
+	return DEFAULT_STATUS_CODE
+}
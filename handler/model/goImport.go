@@ -0,0 +1,507 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/handler"
+)
+
+const (
+	GO_EXT           = ".go"
+	GO_TEST_SUFFIX   = "_test.go"
+	JSON_TAG         = "json"
+	JSON_TAG_SKIP    = "-"
+	JSON_OMITEMPTY   = "omitempty"
+	TIME_PACKAGE     = "time"
+	TIME_TYPE        = "Time"
+	UUID_PACKAGE     = "uuid"
+	UUID_TYPE        = "UUID"
+	TIME_TYPE_EXPR   = TIME_PACKAGE + "." + TIME_TYPE
+	UUID_TYPE_SUFFIX = UUID_PACKAGE + "." + UUID_TYPE
+)
+
+// GoPackage is a type-checked Go package to import models from
+type GoPackage struct {
+	Files []*ast.File
+	Info  *types.Info
+	Types *types.Package
+}
+
+// sourceImporter imports dependencies from source and falls back to an empty
+// package when they cannot be found, so that unresolved types stay invalid
+// instead of aborting the whole type-check
+type sourceImporter struct {
+	source types.Importer
+}
+
+func (si *sourceImporter) Import(importPath string) (*types.Package, error) {
+	if pkg, err := si.source.Import(importPath); err == nil {
+		return pkg, nil
+	}
+	pkg := types.NewPackage(importPath, path.Base(importPath))
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+// LoadGoPackage parses and type-checks the non-test Go files of a directory
+func LoadGoPackage(dir string) (*GoPackage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	files := []*ast.File{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, GO_EXT) || strings.HasSuffix(name, GO_TEST_SUFFIX) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("[ERROR] no Go files found in: %s", dir)
+	}
+
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{
+		Importer: &sourceImporter{source: importer.ForCompiler(fset, "source", nil)},
+		// keep going on errors, unresolved types are mapped from their source expression
+		Error: func(err error) {},
+	}
+	pkg, _ := conf.Check(files[0].Name.Name, fset, files, info)
+
+	return &GoPackage{Files: files, Info: info, Types: pkg}, nil
+}
+
+// LookupStruct finds a named struct type declared in the package
+func (gp *GoPackage) LookupStruct(typeName string) (*types.TypeName, *ast.StructType, error) {
+	for _, file := range gp.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Name.Name != typeName {
+					continue
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					return nil, nil, fmt.Errorf("[ERROR] %s is not a struct type", typeName)
+				}
+				obj, _ := gp.Info.Defs[ts.Name].(*types.TypeName)
+				return obj, st, nil
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("[ERROR] type %s not found", typeName)
+}
+
+// GoModelImporter converts Go struct types into models.
+// Every named struct type becomes its own model, linked by $ref.
+type GoModelImporter struct {
+	Package       *GoPackage
+	DirectoryPath string
+	Models        map[string]*Model // file name -> model
+	Order         []string          // file names in generation order
+	Files         map[string]string // package path + "." + type name -> file name
+	owners        map[string]string // file name -> package path + "." + type name
+	err           error             // first error of the walk, convert does not return one
+}
+
+func NewGoModelImporter(pkg *GoPackage, directoryPath string) *GoModelImporter {
+	return &GoModelImporter{
+		Package:       pkg,
+		DirectoryPath: directoryPath,
+		Models:        make(map[string]*Model),
+		Files:         make(map[string]string),
+		owners:        make(map[string]string),
+	}
+}
+
+// Import converts the named struct type and every named struct it depends on
+func (gi *GoModelImporter) Import(typeName string) error {
+	_, st, err := gi.Package.LookupStruct(typeName)
+	if err != nil {
+		return err
+	}
+
+	gi.importStruct(gi.Package.Types.Path(), typeName, st, nil)
+	return gi.err
+}
+
+// importStruct registers a model for a named struct of the package at
+// pkgPath. Either st (AST) or typed (go/types) describes the struct; AST is
+// used for local declarations. A type whose file name is already taken by a
+// type of another package, e.g. a.Address and b.Address, is prefixed with its
+// package name (b_address).
+func (gi *GoModelImporter) importStruct(pkgPath, typeName string, st *ast.StructType, typed *types.Struct) string {
+	key := pkgPath + "." + typeName
+	if fileName, exists := gi.Files[key]; exists {
+		return fileName
+	}
+
+	title := typeName
+	fileName := ModelFileName(typeName)
+	if _, taken := gi.owners[fileName]; taken {
+		pkgName := path.Base(pkgPath)
+		title = strings.ToUpper(pkgName[:1]) + pkgName[1:] + typeName
+		fileName = ModelFileName(pkgName) + "_" + ModelFileName(typeName)
+	}
+	if owner, taken := gi.owners[fileName]; taken {
+		if gi.err == nil {
+			gi.err = fmt.Errorf("[ERROR] %s and %s both map to the model file %s", owner, key, fileName+fetcher.YAML_EXT)
+		}
+		return fileName
+	}
+
+	m := NewModel(nil, nil, nil)
+	m.DirectoryPath = gi.DirectoryPath
+	m.Title = title
+	// register before walking fields so self references terminate
+	gi.Files[key] = fileName
+	gi.owners[fileName] = key
+	gi.Models[fileName] = m
+	gi.Order = append(gi.Order, fileName)
+
+	if st != nil {
+		gi.addASTFields(m, st)
+	} else if typed != nil {
+		gi.addTypedFields(m, typed)
+	}
+
+	return fileName
+}
+
+func (gi *GoModelImporter) addASTFields(m *Model, st *ast.StructType) {
+	for _, field := range st.Fields.List {
+		tag := ""
+		if field.Tag != nil {
+			tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get(JSON_TAG)
+		}
+		jsonName, omitempty, skip := parseJSONTag(tag)
+		if skip {
+			continue
+		}
+
+		// embedded struct without an explicit json name is flattened
+		if len(field.Names) == 0 && jsonName == "" {
+			if embedded := gi.embeddedAST(field.Type); embedded != nil {
+				gi.addASTFields(m, embedded)
+				continue
+			}
+			if embedded, ok := underlyingStruct(gi.Package.Info.TypeOf(field.Type)); ok {
+				gi.addTypedFields(m, embedded)
+				continue
+			}
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(embeddedName(field.Type))}
+		}
+		for _, ident := range names {
+			if !ident.IsExported() {
+				continue
+			}
+			name := ident.Name
+			if jsonName != "" {
+				name = jsonName
+			}
+			m.Properties[name] = gi.convert(gi.Package.Info.TypeOf(field.Type), field.Type)
+			if !omitempty {
+				m.Required = append(m.Required, name)
+			}
+		}
+	}
+}
+
+func (gi *GoModelImporter) addTypedFields(m *Model, st *types.Struct) {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		jsonName, omitempty, skip := parseJSONTag(reflect.StructTag(st.Tag(i)).Get(JSON_TAG))
+		if skip {
+			continue
+		}
+		if field.Embedded() && jsonName == "" {
+			if embedded, ok := underlyingStruct(field.Type()); ok {
+				gi.addTypedFields(m, embedded)
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		name := field.Name()
+		if jsonName != "" {
+			name = jsonName
+		}
+		m.Properties[name] = gi.convert(field.Type(), nil)
+		if !omitempty {
+			m.Required = append(m.Required, name)
+		}
+	}
+}
+
+// embeddedAST returns the AST of an embedded struct declared in the package
+func (gi *GoModelImporter) embeddedAST(expr ast.Expr) *ast.StructType {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	_, st, err := gi.Package.LookupStruct(ident.Name)
+	if err != nil {
+		return nil
+	}
+	return st
+}
+
+// convert maps a Go type to a property. expr is the source expression of the
+// type when known; it is used for types that could not be resolved.
+func (gi *GoModelImporter) convert(t types.Type, expr ast.Expr) *handler.Property {
+	prop := &handler.Property{}
+
+	if t == nil || t == types.Typ[types.Invalid] {
+		gi.convertExpr(prop, expr)
+		return prop
+	}
+
+	switch tt := t.(type) {
+	case *types.Pointer:
+		var inner ast.Expr
+		if star, ok := expr.(*ast.StarExpr); ok {
+			inner = star.X
+		}
+		prop = gi.convert(tt.Elem(), inner)
+		// $ref siblings are ignored, so nullability can only be kept on inline types
+		if prop.Ref == "" {
+			prop.Nullable = true
+		}
+		return prop
+	case *types.Slice:
+		if basic, ok := tt.Elem().(*types.Basic); ok && basic.Kind() == types.Byte {
+			prop.Type = constants.STRING_TYPE
+			prop.Format = constants.FORMAT_BYTE
+			return prop
+		}
+		prop.Type = constants.ARRAY_TYPE
+		prop.Items = gi.convert(tt.Elem(), elemExpr(expr))
+		return prop
+	case *types.Array:
+		prop.Type = constants.ARRAY_TYPE
+		prop.Items = gi.convert(tt.Elem(), elemExpr(expr))
+		return prop
+	case *types.Map:
+		prop.Type = constants.OBJECT_TYPE
+		return prop
+	case *types.Named:
+		obj := tt.Obj()
+		if obj.Pkg() != nil {
+			switch {
+			case obj.Pkg().Path() == TIME_PACKAGE && obj.Name() == TIME_TYPE:
+				prop.Type = constants.STRING_TYPE
+				prop.Format = constants.FORMAT_DATE_TIME
+				return prop
+			case path.Base(obj.Pkg().Path()) == UUID_PACKAGE && obj.Name() == UUID_TYPE:
+				prop.Type = constants.STRING_TYPE
+				prop.Format = constants.FORMAT_UUID
+				return prop
+			}
+		}
+		if st, ok := tt.Underlying().(*types.Struct); ok {
+			var local *ast.StructType
+			if obj.Pkg() == gi.Package.Types {
+				_, local, _ = gi.Package.LookupStruct(obj.Name())
+			}
+			pkgPath := ""
+			if obj.Pkg() != nil {
+				pkgPath = obj.Pkg().Path()
+			}
+			fileName := gi.importStruct(pkgPath, obj.Name(), local, st)
+			ref, _ := fetcher.BuildRef(gi.DirectoryPath, filepath.Join(gi.DirectoryPath, fileName+fetcher.YAML_EXT), "")
+			prop.Ref = ref
+			return prop
+		}
+		return gi.convert(tt.Underlying(), nil)
+	case *types.Struct:
+		prop.Type = constants.OBJECT_TYPE
+		inline := NewModel(nil, nil, nil)
+		gi.addTypedFields(inline, tt)
+		prop.Properties = inline.Properties
+		prop.Required = inline.Required
+		return prop
+	case *types.Basic:
+		convertBasic(prop, tt)
+		return prop
+	}
+
+	// interfaces and anything else stay untyped
+	return prop
+}
+
+// convertExpr maps a type from its source expression, used when go/types
+// could not resolve it (e.g. a dependency that is not available)
+func (gi *GoModelImporter) convertExpr(prop *handler.Property, expr ast.Expr) {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		gi.convertExpr(prop, e.X)
+		if prop.Ref == "" {
+			prop.Nullable = true
+		}
+	case *ast.ArrayType:
+		prop.Type = constants.ARRAY_TYPE
+		prop.Items = &handler.Property{}
+		gi.convertExpr(prop.Items, e.Elt)
+	case *ast.SelectorExpr:
+		switch name := types.ExprString(e); {
+		case name == TIME_TYPE_EXPR:
+			prop.Type = constants.STRING_TYPE
+			prop.Format = constants.FORMAT_DATE_TIME
+		case strings.HasSuffix(name, UUID_TYPE_SUFFIX):
+			prop.Type = constants.STRING_TYPE
+			prop.Format = constants.FORMAT_UUID
+		}
+	}
+}
+
+func convertBasic(prop *handler.Property, basic *types.Basic) {
+	switch basic.Kind() {
+	case types.Bool:
+		prop.Type = constants.BOOLEAN_TYPE
+	case types.String:
+		prop.Type = constants.STRING_TYPE
+	case types.Int8, types.Int16, types.Int32, types.Uint8, types.Uint16, types.Uint32:
+		prop.Type = constants.INTEGER_TYPE
+		prop.Format = constants.FORMAT_INT32
+	case types.Int, types.Int64, types.Uint, types.Uint64, types.Uintptr:
+		prop.Type = constants.INTEGER_TYPE
+		prop.Format = constants.FORMAT_INT64
+	case types.Float32:
+		prop.Type = constants.NUMBER_TYPE
+		prop.Format = constants.FORMAT_FLOAT
+	case types.Float64:
+		prop.Type = constants.NUMBER_TYPE
+		prop.Format = constants.FORMAT_DOUBLE
+	}
+}
+
+func elemExpr(expr ast.Expr) ast.Expr {
+	if arr, ok := expr.(*ast.ArrayType); ok {
+		return arr.Elt
+	}
+	return nil
+}
+
+func underlyingStruct(t types.Type) (*types.Struct, bool) {
+	if t == nil {
+		return nil, false
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	return st, ok
+}
+
+func embeddedName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+// parseJSONTag returns the json name, whether omitempty is set and whether the field is skipped
+func parseJSONTag(tag string) (string, bool, bool) {
+	if tag == JSON_TAG_SKIP {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	omitempty := false
+	for _, opt := range parts[1:] {
+		if opt == JSON_OMITEMPTY {
+			omitempty = true
+		}
+	}
+	return parts[0], omitempty, false
+}
+
+// ModelFileName converts a Go type name into a model file name (UserProfile -> user_profile)
+func ModelFileName(typeName string) string {
+	var b strings.Builder
+	runes := []rune(typeName)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// start a new word at lower->Upper and at the last upper of an acronym (IDToken -> id_token)
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// HandleImportGoCommand converts a Go struct type (and the named structs it
+// depends on) into model files
+func (mh *ModelHandler) HandleImportGoCommand(dir, typeName string) error {
+	if typeName == "" {
+		return errors.New("[ERROR] specify the struct type to import (--type)")
+	}
+
+	pkg, err := LoadGoPackage(dir)
+	if err != nil {
+		return err
+	}
+
+	model := NewModel(mh.Input, mh.Validator, mh.DirectoryFetcher)
+	if err := model.InputDirectoryToGenerate(); err != nil {
+		return err
+	}
+
+	goImporter := NewGoModelImporter(pkg, model.DirectoryPath)
+	if err := goImporter.Import(typeName); err != nil {
+		return err
+	}
+
+	for _, fileName := range goImporter.Order {
+		if err := goImporter.Models[fileName].GenerateModel(fileName); err != nil {
+			return err
+		}
+		fmt.Printf("[INFO] Generated %s\n", filepath.Join(model.DirectoryPath, fileName+fetcher.YAML_EXT))
+	}
+
+	return nil
+}
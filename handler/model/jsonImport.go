@@ -0,0 +1,147 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/handler"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// formatDetectors are tried in order, the first match wins
+var formatDetectors = []struct {
+	format string
+	match  func(value string) bool
+}{
+	{constants.FORMAT_UUID, uuidPattern.MatchString},
+	{constants.FORMAT_DATE_TIME, func(v string) bool {
+		_, err := time.Parse(time.RFC3339, v)
+		return err == nil
+	}},
+	{constants.FORMAT_DATE, func(v string) bool {
+		_, err := time.Parse(time.DateOnly, v)
+		return err == nil
+	}},
+	{constants.FORMAT_EMAIL, func(v string) bool {
+		addr, err := mail.ParseAddress(v)
+		return err == nil && addr.Address == v
+	}},
+	{constants.FORMAT_IPV4, func(v string) bool {
+		ip := net.ParseIP(v)
+		return ip != nil && ip.To4() != nil && strings.Contains(v, ".")
+	}},
+	{constants.FORMAT_IPV6, func(v string) bool {
+		ip := net.ParseIP(v)
+		return ip != nil && strings.Contains(v, ":")
+	}},
+	{constants.FORMAT_URI, func(v string) bool {
+		u, err := url.Parse(v)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	}},
+}
+
+// DetectStringFormat returns the format of a string value, or "" when none matches.
+// Only formats listed in constants.FormatStringList are detected.
+func DetectStringFormat(value string) string {
+	for _, detector := range formatDetectors {
+		if !slices.Contains(constants.FormatStringList, detector.format) {
+			continue
+		}
+		if detector.match(value) {
+			return detector.format
+		}
+	}
+	return ""
+}
+
+// ReadJSONSample reads a JSON payload and returns the object to infer a model from.
+// When the payload is an array, its first element is used.
+func ReadJSONSample(file string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("[ERROR] failed to parse JSON: %s", file)
+	}
+
+	if list, ok := value.([]interface{}); ok {
+		if len(list) == 0 {
+			return nil, fmt.Errorf("[ERROR] JSON array is empty: %s", file)
+		}
+		value = list[0]
+	}
+
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("[ERROR] JSON sample must be an object or an array of objects")
+	}
+
+	return obj, nil
+}
+
+// InferProperties infers a property for every key of a JSON object
+func (m *Model) InferProperties(obj map[string]interface{}) {
+	for name, value := range obj {
+		m.Properties[name] = m.inferProperty(name, nil, value)
+	}
+}
+
+func (m *Model) inferProperty(name string, parent *handler.Property, value interface{}) *handler.Property {
+	prop := handler.NewProperty(m.Input, name, parent, &handler.Optionals{}, constants.MODE_MODEL, nil, m.DirectoryPath)
+
+	switch v := value.(type) {
+	case nil:
+		// the type cannot be told from null, string is the safest guess
+		prop.Type = constants.STRING_TYPE
+		prop.Nullable = true
+	case bool:
+		prop.Type = constants.BOOLEAN_TYPE
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			prop.Type = constants.INTEGER_TYPE
+			if i > math.MaxInt32 || i < math.MinInt32 {
+				prop.Format = constants.FORMAT_INT64
+			}
+		} else {
+			prop.Type = constants.NUMBER_TYPE
+		}
+	case string:
+		prop.Type = constants.STRING_TYPE
+		prop.Format = DetectStringFormat(v)
+	case []interface{}:
+		prop.Type = constants.ARRAY_TYPE
+		var first interface{}
+		if len(v) > 0 {
+			first = v[0]
+		}
+		prop.Items = m.inferProperty(name, prop, first)
+		// an empty array says nothing about nullability of its items
+		prop.Items.Nullable = false
+	case map[string]interface{}:
+		prop.Type = constants.OBJECT_TYPE
+		for childName, childValue := range v {
+			prop.Properties[childName] = m.inferProperty(childName, prop, childValue)
+		}
+	}
+
+	return prop
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"sort"
+
 	"github.com/Daaaai0809/swagen-v2/fetcher"
 	"github.com/Daaaai0809/swagen-v2/input"
 	"github.com/Daaaai0809/swagen-v2/validator"
@@ -52,3 +54,47 @@ func (mh *ModelHandler) HandleGenerateModelCommand() error {
 
 	return nil
 }
+
+// HandleImportJSONCommand infers a model from a sample JSON payload,
+// lets the user confirm or adjust each inferred field and writes the model
+func (mh *ModelHandler) HandleImportJSONCommand(jsonFile string) error {
+	sample, err := ReadJSONSample(jsonFile)
+	if err != nil {
+		return err
+	}
+
+	model := NewModel(mh.Input, mh.Validator, mh.DirectoryFetcher)
+
+	if err := model.InputDirectoryToGenerate(); err != nil {
+		return err
+	}
+
+	var fileName string
+	if err := mh.Input.StringInput(&fileName, "Enter the model file name (without extension)", mh.Validator.Validator_Alphanumeric_Underscore()); err != nil {
+		return err
+	}
+
+	if err := model.ReadTitle(); err != nil {
+		return err
+	}
+
+	model.InferProperties(sample)
+
+	names := make([]string, 0, len(model.Properties))
+	for name := range model.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := model.Properties[name].ConfirmInferred(); err != nil {
+			return err
+		}
+	}
+
+	if err := model.GenerateModel(fileName); err != nil {
+		return err
+	}
+
+	return nil
+}
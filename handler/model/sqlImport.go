@@ -0,0 +1,468 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/handler"
+)
+
+var (
+	createTablePattern = regexp.MustCompile(`(?is)^\s*create\s+(?:(?:global|local)\s+)?(?:temporary\s+|temp\s+)?table\s+(?:if\s+not\s+exists\s+)?([^\s(]+)\s*\(`)
+)
+
+// tableConstraintKeywords start a table level constraint instead of a column
+var tableConstraintKeywords = []string{"constraint", "primary", "unique", "key", "index", "foreign", "check", "fulltext", "spatial", "exclude"}
+
+// columnConstraintKeywords end the type part of a column definition
+var columnConstraintKeywords = []string{
+	"not", "null", "default", "primary", "unique", "references", "check", "auto_increment", "autoincrement",
+	"comment", "generated", "collate", "constraint", "on", "identity", "key", "as",
+}
+
+// typeModifierKeywords are dropped from the column type (int unsigned -> int)
+var typeModifierKeywords = []string{"unsigned", "signed", "zerofill"}
+
+// SQLColumn is a column of a CREATE TABLE statement
+type SQLColumn struct {
+	Name    string
+	Type    string // lower-cased base type (e.g. "character varying", "timestamp with time zone")
+	Args    []string
+	IsArray bool
+	NotNull bool
+}
+
+// SQLTable is a parsed CREATE TABLE statement
+type SQLTable struct {
+	Name    string
+	Columns []*SQLColumn
+}
+
+// ParseSQLFile parses every CREATE TABLE statement of a DDL file
+func ParseSQLFile(file string) ([]*SQLTable, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSQL(string(data))
+}
+
+// ParseSQL parses CREATE TABLE statements written for PostgreSQL or MySQL.
+// Other statements are ignored.
+func ParseSQL(ddl string) ([]*SQLTable, error) {
+	ddl = stripComments(ddl)
+
+	tables := []*SQLTable{}
+	for _, stmt := range splitTopLevel(ddl, ';') {
+		match := createTablePattern.FindStringSubmatchIndex(stmt)
+		if match == nil {
+			continue
+		}
+
+		name := unquoteIdentifier(stmt[match[2]:match[3]])
+		bodyStart := match[1]
+		bodyEnd := matchingParen(stmt, bodyStart-1)
+		if bodyEnd < 0 {
+			return nil, fmt.Errorf("[ERROR] unbalanced parentheses in CREATE TABLE %s", name)
+		}
+
+		table, err := parseTableBody(name, stmt[bodyStart:bodyEnd])
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+func parseTableBody(name string, body string) (*SQLTable, error) {
+	table := &SQLTable{Name: name}
+	primaryKeys := []string{}
+
+	for _, def := range splitTopLevel(body, ',') {
+		words := tokenizeDefinition(def)
+		if len(words) == 0 {
+			continue
+		}
+
+		if slices.Contains(tableConstraintKeywords, strings.ToLower(words[0])) {
+			if pk := parsePrimaryKeyConstraint(words); pk != nil {
+				primaryKeys = append(primaryKeys, pk...)
+			}
+			continue
+		}
+
+		column, err := parseColumn(words)
+		if err != nil {
+			return nil, fmt.Errorf("[ERROR] table %s: %v", name, err)
+		}
+		table.Columns = append(table.Columns, column)
+	}
+
+	for _, column := range table.Columns {
+		if slices.Contains(primaryKeys, column.Name) {
+			column.NotNull = true
+		}
+	}
+
+	return table, nil
+}
+
+func parseColumn(words []string) (*SQLColumn, error) {
+	if len(words) < 2 {
+		return nil, fmt.Errorf("column %q has no type", strings.Join(words, " "))
+	}
+
+	column := &SQLColumn{Name: unquoteIdentifier(words[0])}
+
+	typeWords := []string{}
+	i := 1
+	for ; i < len(words); i++ {
+		word := strings.ToLower(words[i])
+		if strings.HasPrefix(word, "(") {
+			column.Args = splitTypeArgs(word)
+			continue
+		}
+		if word == "[]" {
+			column.IsArray = true
+			continue
+		}
+		// MySQL "CHARACTER SET utf8" after the type
+		if word == "character" && len(typeWords) > 0 && i+1 < len(words) && strings.EqualFold(words[i+1], "set") {
+			break
+		}
+		if slices.Contains(columnConstraintKeywords, word) {
+			break
+		}
+		if slices.Contains(typeModifierKeywords, word) {
+			continue
+		}
+		typeWords = append(typeWords, word)
+	}
+	column.Type = strings.Join(typeWords, " ")
+
+	constraints := words[i:]
+	column.NotNull = hasKeywords(constraints, "not", "null") || hasKeywords(constraints, "primary", "key")
+
+	return column, nil
+}
+
+// hasKeywords reports whether the words contain the keyword sequence.
+// Parenthesized groups and string literals are single words, so CHECK (...)
+// and DEFAULT '...' never match, and neither does the IS NOT NULL of an
+// expression.
+func hasKeywords(words []string, keywords ...string) bool {
+	for i := 0; i+len(keywords) <= len(words); i++ {
+		if i > 0 && strings.EqualFold(words[i-1], "is") {
+			continue
+		}
+		matched := true
+		for j, keyword := range keywords {
+			if !strings.EqualFold(words[i+j], keyword) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePrimaryKeyConstraint returns the columns of a PRIMARY KEY (a, b) constraint
+func parsePrimaryKeyConstraint(words []string) []string {
+	for i := 0; i+2 < len(words); i++ {
+		if strings.EqualFold(words[i], "primary") && strings.EqualFold(words[i+1], "key") && strings.HasPrefix(words[i+2], "(") {
+			columns := []string{}
+			for _, c := range splitTypeArgs(words[i+2]) {
+				columns = append(columns, unquoteIdentifier(c))
+			}
+			return columns
+		}
+	}
+	return nil
+}
+
+// ToProperty maps the column type to a property
+func (c *SQLColumn) ToProperty() *handler.Property {
+	prop := &handler.Property{}
+	mapSQLType(prop, c.Type, c.Args)
+
+	if c.IsArray {
+		prop = &handler.Property{Type: constants.ARRAY_TYPE, Items: prop}
+	}
+	prop.Nullable = !c.NotNull
+
+	return prop
+}
+
+func mapSQLType(prop *handler.Property, sqlType string, args []string) {
+	base := sqlType
+	// pg array written as "integer[]" arrives glued to the type
+	base = strings.TrimSuffix(base, "[]")
+
+	switch {
+	case base == "uuid" || base == "uniqueidentifier":
+		prop.Type = constants.STRING_TYPE
+		prop.Format = constants.FORMAT_UUID
+	case slices.Contains([]string{"varchar", "character varying", "char", "character", "nvarchar", "nchar", "varchar2", "bpchar"}, base):
+		prop.Type = constants.STRING_TYPE
+		if len(args) > 0 {
+			if n, err := strconv.Atoi(args[0]); err == nil {
+				prop.MaxLength = n
+			}
+		}
+	case strings.HasPrefix(base, "timestamp") || base == "datetime" || base == "datetime2" || base == "datetimeoffset":
+		prop.Type = constants.STRING_TYPE
+		prop.Format = constants.FORMAT_DATE_TIME
+	case base == "date":
+		prop.Type = constants.STRING_TYPE
+		prop.Format = constants.FORMAT_DATE
+	case base == "tinyint" && len(args) > 0 && args[0] == "1":
+		// MySQL boolean
+		prop.Type = constants.BOOLEAN_TYPE
+	case slices.Contains([]string{"bool", "boolean"}, base) || (base == "bit" && (len(args) == 0 || args[0] == "1")):
+		prop.Type = constants.BOOLEAN_TYPE
+	case slices.Contains([]string{"bigint", "int8", "bigserial", "serial8"}, base):
+		prop.Type = constants.INTEGER_TYPE
+		prop.Format = constants.FORMAT_INT64
+	case slices.Contains([]string{"int", "integer", "int4", "int2", "smallint", "tinyint", "mediumint", "serial", "serial4", "smallserial", "serial2", "year"}, base):
+		prop.Type = constants.INTEGER_TYPE
+		prop.Format = constants.FORMAT_INT32
+	case slices.Contains([]string{"real", "float4"}, base):
+		prop.Type = constants.NUMBER_TYPE
+		prop.Format = constants.FORMAT_FLOAT
+	case slices.Contains([]string{"double", "double precision", "float8"}, base):
+		prop.Type = constants.NUMBER_TYPE
+		prop.Format = constants.FORMAT_DOUBLE
+	case slices.Contains([]string{"numeric", "decimal", "float", "money", "dec", "fixed"}, base):
+		prop.Type = constants.NUMBER_TYPE
+	case slices.Contains([]string{"json", "jsonb"}, base):
+		prop.Type = constants.OBJECT_TYPE
+	case slices.Contains([]string{"bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary"}, base):
+		prop.Type = constants.STRING_TYPE
+		prop.Format = constants.FORMAT_BINARY
+	default:
+		// text, enum, time, inet and anything unknown are kept as plain strings
+		prop.Type = constants.STRING_TYPE
+	}
+}
+
+// ToModel converts the table into a model with one property per column
+func (t *SQLTable) ToModel(directoryPath string) *Model {
+	m := NewModel(nil, nil, nil)
+	m.DirectoryPath = directoryPath
+	m.Title = tableTitle(t.Name)
+	for _, column := range t.Columns {
+		m.Properties[column.Name] = column.ToProperty()
+		if column.NotNull {
+			m.Required = append(m.Required, column.Name)
+		}
+	}
+	return m
+}
+
+// ModelFileNameFromTable returns a valid model file name for a table (user-accounts -> user_accounts)
+func ModelFileNameFromTable(table string) string {
+	var b strings.Builder
+	for _, r := range table {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name
+}
+
+func tableTitle(table string) string {
+	parts := strings.FieldsFunc(table, func(r rune) bool { return r == '_' || r == '-' || r == ' ' })
+	for i, p := range parts {
+		parts[i] = strings.ToUpper(p[:1]) + p[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// splitTopLevel splits s on sep outside of parentheses and quotes
+func splitTopLevel(s string, sep rune) []string {
+	parts := []string{}
+	depth := 0
+	var quote rune
+	start := 0
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, s[start:])
+	return parts
+}
+
+// stripComments removes -- and # line comments and /* */ block comments
+// outside of '…', "…" and `…` quotes
+func stripComments(s string) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '#' || (c == '-' && strings.HasPrefix(s[i:], "--")):
+			end := strings.IndexByte(s[i:], '\n')
+			if end < 0 {
+				return b.String()
+			}
+			i += end - 1
+			continue
+		case c == '/' && strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+			// a comment separates tokens like whitespace
+			b.WriteByte(' ')
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open
+func matchingParen(s string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// tokenizeDefinition splits a column or constraint definition into words,
+// keeping quoted identifiers, parenthesized groups and [] as single tokens
+func tokenizeDefinition(def string) []string {
+	words := []string{}
+	def = strings.TrimSpace(def)
+	for i := 0; i < len(def); {
+		c := def[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '(':
+			end := matchingParen(def, i)
+			if end < 0 {
+				end = len(def) - 1
+			}
+			words = append(words, def[i:end+1])
+			i = end + 1
+		case c == '[' && i+1 < len(def) && def[i+1] == ']':
+			words = append(words, "[]")
+			i += 2
+		case c == '"' || c == '`' || c == '\'':
+			end := strings.IndexByte(def[i+1:], c)
+			if end < 0 {
+				end = len(def) - i - 2
+			}
+			words = append(words, def[i:i+end+2])
+			i += end + 2
+		default:
+			j := i
+			for j < len(def) && !unicode.IsSpace(rune(def[j])) && def[j] != '(' && def[j] != '[' {
+				j++
+			}
+			words = append(words, def[i:j])
+			i = j
+		}
+	}
+	return words
+}
+
+func splitTypeArgs(group string) []string {
+	inner := strings.TrimSuffix(strings.TrimPrefix(group, "("), ")")
+	args := []string{}
+	for _, a := range splitTopLevel(inner, ',') {
+		args = append(args, strings.TrimSpace(a))
+	}
+	return args
+}
+
+// unquoteIdentifier strips quotes and the schema of an identifier ("public"."users" -> users)
+func unquoteIdentifier(ident string) string {
+	if i := strings.LastIndex(ident, "."); i >= 0 {
+		ident = ident[i+1:]
+	}
+	return strings.Trim(ident, "\"`[]")
+}
+
+// HandleImportSQLCommand generates one model per CREATE TABLE statement of a DDL file
+func (mh *ModelHandler) HandleImportSQLCommand(sqlFile string) error {
+	tables, err := ParseSQLFile(sqlFile)
+	if err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return fmt.Errorf("[ERROR] no CREATE TABLE statement found in: %s", sqlFile)
+	}
+
+	model := NewModel(mh.Input, mh.Validator, mh.DirectoryFetcher)
+	if err := model.InputDirectoryToGenerate(); err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		fileName := ModelFileNameFromTable(table.Name)
+		if err := table.ToModel(model.DirectoryPath).GenerateModel(fileName); err != nil {
+			return err
+		}
+		fmt.Printf("[INFO] Generated %s\n", filepath.Join(model.DirectoryPath, fileName+fetcher.YAML_EXT))
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSQLKeepsCommentMarkersInsideQuotes(t *testing.T) {
+	ddl := `CREATE TABLE widgets (
+  id BIGINT NOT NULL, -- surrogate key
+  color VARCHAR(7) DEFAULT '#ffffff',
+  name VARCHAR(64) NOT NULL, # display name
+  note TEXT COMMENT 'see -- docs',
+  created_at TIMESTAMP /* set by the database */ NOT NULL,
+  PRIMARY KEY (id)
+);`
+
+	tables, err := ParseSQL(ddl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("got %d tables, want 1", len(tables))
+	}
+
+	names := []string{}
+	notNull := map[string]bool{}
+	for _, column := range tables[0].Columns {
+		names = append(names, column.Name)
+		notNull[column.Name] = column.NotNull
+	}
+	if want := []string{"id", "color", "name", "note", "created_at"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("columns = %v, want %v", names, want)
+	}
+	if !notNull["id"] || !notNull["name"] || !notNull["created_at"] || notNull["color"] || notNull["note"] {
+		t.Errorf("not null = %v", notNull)
+	}
+}
+
+func TestParseSQLNotNull(t *testing.T) {
+	tests := []struct {
+		definition string
+		notNull    bool
+	}{
+		{"a INT NOT NULL", true},
+		{"a INT not null DEFAULT 0", true},
+		{"a INT PRIMARY KEY", true},
+		{"a INT", false},
+		{"a INT NULL", false},
+		{"a INT CHECK (a IS NOT NULL)", false},
+		{"a INT CHECK(a IS NOT NULL) NOT NULL", true},
+		{"a VARCHAR(8) DEFAULT 'not null'", false},
+		{"a VARCHAR(8) COMMENT 'primary key'", false},
+		{"a INT GENERATED ALWAYS AS (b IS NOT NULL) STORED", false},
+	}
+
+	for _, tt := range tests {
+		tables, err := ParseSQL("CREATE TABLE t (" + tt.definition + ");")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.definition, err)
+		}
+		if got := tables[0].Columns[0].NotNull; got != tt.notNull {
+			t.Errorf("%s: not null = %v, want %v", tt.definition, got, tt.notNull)
+		}
+	}
+}
@@ -16,9 +16,11 @@ type Model struct {
 	DirectoryFetcher fetcher.IDirectoryFetcher `yaml:"-"`
 	DirectoryPath    string                    `yaml:"-"`
 
-	Title      string                       `yaml:"title,omitempty"`
-	Type       string                       `yaml:"type"`
-	Properties map[string]*handler.Property `yaml:"properties,omitempty"`
+	Title       string                       `yaml:"title,omitempty"`
+	Description string                       `yaml:"description,omitempty"`
+	Type        string                       `yaml:"type"`
+	Properties  map[string]*handler.Property `yaml:"properties,omitempty"`
+	Required    []string                     `yaml:"required,omitempty"`
 }
 
 func NewModel(input input.IInputMethods, validator validator.IInputValidator, directoryFetcher fetcher.IDirectoryFetcher) *Model {
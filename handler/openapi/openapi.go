@@ -0,0 +1,52 @@
+package openapi
+
+import (
+	"fmt"
+
+	"github.com/Daaaai0809/swagen-v2/utils"
+)
+
+type OpenAPIHandler struct{}
+
+func NewOpenAPIHandler() *OpenAPIHandler {
+	return &OpenAPIHandler{}
+}
+
+// HandleImportCommand splits a monolithic OpenAPI document into model, schema
+// and path files under SWAGEN_MODEL_PATH, SWAGEN_SCHEMA_PATH and SWAGEN_API_PATH,
+// then prints a report of everything that could not be placed
+func (oh *OpenAPIHandler) HandleImportCommand(file string, force bool) error {
+	importer := NewImporter(
+		utils.GetEnv(utils.SWAGEN_MODEL_PATH, ""),
+		utils.GetEnv(utils.SWAGEN_SCHEMA_PATH, ""),
+		utils.GetEnv(utils.SWAGEN_API_PATH, ""),
+	)
+
+	if err := importer.Load(file); err != nil {
+		return err
+	}
+
+	if err := importer.Convert(); err != nil {
+		return err
+	}
+
+	written, err := importer.WriteFiles(force)
+	for _, path := range written {
+		fmt.Printf("[INFO] Wrote %s\n", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(importer.Report) == 0 {
+		fmt.Println("[INFO] Everything was placed.")
+		return nil
+	}
+
+	fmt.Printf("[WARN] %d item(s) could not be placed:\n", len(importer.Report))
+	for _, item := range importer.Report {
+		fmt.Printf("  - %s\n", item)
+	}
+
+	return nil
+}
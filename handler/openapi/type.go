@@ -0,0 +1,659 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/handler/api"
+	"github.com/Daaaai0809/swagen-v2/handler/model"
+	"github.com/Daaaai0809/swagen-v2/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	COMPONENTS_SCHEMAS_REF        = "#/components/schemas/"
+	COMPONENTS_PARAMETERS_REF     = "#/components/parameters/"
+	COMPONENTS_REQUEST_BODIES_REF = "#/components/requestBodies/"
+	COMPONENTS_RESPONSES_REF      = "#/components/responses/"
+
+	KEY_REF = "$ref"
+	NULL    = "null"
+)
+
+// schemaFileNamePattern matches schema names which are usable as file names as is
+var schemaFileNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// requestResponseNamePattern marks component schemas that are written as schema files instead of models
+var requestResponseNamePattern = regexp.MustCompile(`(Request|Response|Body|Params|Payload|Input|Output)$`)
+
+// ignoredSchemaKeys carry documentation only and are dropped silently
+var ignoredSchemaKeys = []string{"title", "xml", "externalDocs"}
+
+// topLevelPlacedKeys are the top-level keys whose content is placed into files
+var topLevelPlacedKeys = []string{"openapi", "info", "paths", "components"}
+
+// GeneratedFile is a file which is written by the import command
+type GeneratedFile struct {
+	Path string
+	Data []byte
+}
+
+type placement struct {
+	isModel bool
+	file    string
+}
+
+// Importer splits a monolithic OpenAPI document into model, schema and path files
+type Importer struct {
+	ModelRoot  string
+	SchemaRoot string
+	APIRoot    string
+
+	Files  []*GeneratedFile
+	Report []string
+
+	doc        map[string]interface{}
+	placements map[string]*placement // component schema name -> file
+	used       map[string]bool       // inlined component refs
+}
+
+func NewImporter(modelRoot, schemaRoot, apiRoot string) *Importer {
+	return &Importer{
+		ModelRoot:  modelRoot,
+		SchemaRoot: schemaRoot,
+		APIRoot:    apiRoot,
+		placements: make(map[string]*placement),
+		used:       make(map[string]bool),
+	}
+}
+
+// Load parses an OpenAPI document written in YAML or JSON
+func (im *Importer) Load(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("[ERROR] failed to parse OpenAPI document: %s", file)
+	}
+
+	doc, ok := normalize(raw).(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("[ERROR] OpenAPI document is not an object: %s", file)
+	}
+	im.doc = doc
+	return nil
+}
+
+// Convert builds every file from the loaded document and collects anything it could not place
+func (im *Importer) Convert() error {
+	if im.doc == nil {
+		return fmt.Errorf("[ERROR] no OpenAPI document loaded")
+	}
+
+	schemas := asMap(im.lookup("components", "schemas"))
+	im.placeSchemas(schemas)
+
+	for _, name := range sortedKeys(schemas) {
+		p, ok := im.placements[name]
+		if !ok {
+			continue
+		}
+		var err error
+		if p.isModel {
+			err = im.convertModel(name, schemas[name], p)
+		} else {
+			err = im.convertSchema(name, schemas[name], p)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	paths := asMap(im.doc["paths"])
+	for _, template := range sortedKeys(paths) {
+		if err := im.convertPathItem(template, asMap(paths[template])); err != nil {
+			return err
+		}
+	}
+
+	im.reportUnplaced()
+	return nil
+}
+
+func (im *Importer) placeSchemas(schemas map[string]interface{}) {
+	taken := map[string]string{}
+	for _, name := range sortedKeys(schemas) {
+		node := asMap(schemas[name])
+		p := &placement{}
+
+		_, hasProperties := node["properties"]
+		isObject := node["type"] == constants.OBJECT_TYPE || (node["type"] == nil && hasProperties)
+		if isObject && node[KEY_REF] == nil && !requestResponseNamePattern.MatchString(name) {
+			p.isModel = true
+			p.file = filepath.Join(im.ModelRoot, model.ModelFileName(safeFileName(name))+fetcher.YAML_EXT)
+		} else {
+			p.file = filepath.Join(im.SchemaRoot, safeFileName(name)+fetcher.YAML_EXT)
+		}
+
+		if other, exists := taken[p.file]; exists {
+			im.addReport("components/schemas/"+name, fmt.Sprintf("file %s is already used by %s", p.file, other))
+			continue
+		}
+		taken[p.file] = name
+		im.placements[name] = p
+	}
+}
+
+func (im *Importer) convertModel(name string, node interface{}, p *placement) error {
+	loc := "components/schemas/" + name
+	root := im.toProperty(node, filepath.Dir(p.file), loc)
+
+	m := model.NewModel(nil, nil, nil)
+	m.Title = name
+	if title, ok := asMap(node)["title"].(string); ok && title != "" {
+		m.Title = title
+	}
+	m.Description = root.Description
+	m.Properties = root.Properties
+	m.Required = root.Required
+	if root.Nullable || root.ReadOnly || len(root.Enum) > 0 || root.Example != "" {
+		im.addReport(loc, "root nullable/readOnly/enum/example are not kept on models")
+	}
+
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+	im.Files = append(im.Files, &GeneratedFile{Path: p.file, Data: data})
+	return nil
+}
+
+func (im *Importer) convertSchema(name string, node interface{}, p *placement) error {
+	root := im.toProperty(node, filepath.Dir(p.file), "components/schemas/"+name)
+
+	data, err := yaml.Marshal(map[string]*handler.Property{name: root})
+	if err != nil {
+		return err
+	}
+	im.Files = append(im.Files, &GeneratedFile{Path: p.file, Data: data})
+	return nil
+}
+
+func (im *Importer) convertPathItem(template string, item map[string]interface{}) error {
+	loc := "paths[" + template + "]"
+	file := utils.PathFileFromTemplate(im.APIRoot, template)
+	dir := filepath.Dir(file)
+
+	if ref, ok := item[KEY_REF].(string); ok {
+		im.addReport(loc, "path item $ref is not supported: "+ref)
+		return nil
+	}
+
+	sharedParameters := asSlice(item["parameters"])
+	apiMap := api.APIMap{}
+
+	for _, key := range sortedKeys(item) {
+		switch {
+		case isMethod(key):
+			apiMap[key] = im.convertOperation(asMap(item[key]), sharedParameters, dir, loc+"/"+key)
+		case key == "parameters":
+			// merged into every operation
+		case key == "summary" || key == "description":
+			im.addReport(loc, "path level "+key+" is not kept")
+		default:
+			im.addReport(loc, "dropped key "+key)
+		}
+	}
+
+	if len(apiMap) == 0 {
+		im.addReport(loc, "no operations")
+		return nil
+	}
+
+	data, err := apiMap.ToYaml()
+	if err != nil {
+		return err
+	}
+	im.Files = append(im.Files, &GeneratedFile{Path: file, Data: data})
+	return nil
+}
+
+func (im *Importer) convertOperation(op map[string]interface{}, sharedParameters []interface{}, dir, loc string) *api.API {
+	operation := api.NewAPI(nil, nil, nil, nil)
+
+	// operation parameters override path level ones with the same name and location
+	parameters := []map[string]interface{}{}
+	seen := map[string]int{}
+	for _, raw := range append(append([]interface{}{}, sharedParameters...), asSlice(op["parameters"])...) {
+		param := asMap(im.resolveComponent(raw, COMPONENTS_PARAMETERS_REF, loc))
+		key := fmt.Sprint(param["in"], "/", param["name"])
+		if i, exists := seen[key]; exists {
+			parameters[i] = param
+			continue
+		}
+		seen[key] = len(parameters)
+		parameters = append(parameters, param)
+	}
+	for _, param := range parameters {
+		operation.Parameters = append(operation.Parameters, im.convertParameter(param, dir, loc))
+	}
+
+	for _, key := range sortedKeys(op) {
+		value := op[key]
+		switch key {
+		case "operationId":
+			operation.OperationID = fmt.Sprint(value)
+		case "summary":
+			operation.Summary = fmt.Sprint(value)
+		case "description":
+			operation.Description = fmt.Sprint(value)
+		case "tags":
+			for _, tag := range asSlice(value) {
+				operation.Tags = append(operation.Tags, fmt.Sprint(tag))
+			}
+		case "deprecated":
+			operation.Deprecated, _ = value.(bool)
+		case "parameters":
+			// handled above
+		case "requestBody":
+			operation.RequestBody = im.convertRequestBody(asMap(im.resolveComponent(value, COMPONENTS_REQUEST_BODIES_REF, loc)), dir, loc+"/requestBody")
+		case "responses":
+			responses := asMap(value)
+			for _, code := range sortedKeys(responses) {
+				resolved := asMap(im.resolveComponent(responses[code], COMPONENTS_RESPONSES_REF, loc))
+				operation.Responses[code] = im.convertResponse(code, resolved, dir, loc+"/responses/"+code)
+			}
+		default:
+			im.addReport(loc, "dropped key "+key)
+		}
+	}
+
+	return operation
+}
+
+func (im *Importer) convertParameter(param map[string]interface{}, dir, loc string) *api.Parameter {
+	name := fmt.Sprint(param["name"])
+	parameter := &api.Parameter{
+		In:   fmt.Sprint(param["in"]),
+		Name: name,
+	}
+	if required, ok := param["required"].(bool); ok {
+		parameter.Required = required
+	}
+
+	for _, key := range sortedKeys(param) {
+		switch key {
+		case "name", "in", "required":
+		case "deprecated":
+			parameter.Deprecated, _ = param[key].(bool)
+		case "schema":
+			prop := im.toProperty(param[key], dir, loc+"/parameters/"+name)
+			parameter.Schema = &api.ParamSchema{
+				Type:    prop.Type,
+				Format:  prop.Format,
+				Example: prop.Example,
+				Ref:     prop.Ref,
+			}
+			if prop.Items != nil || len(prop.Properties) > 0 || len(prop.Enum) > 0 || prop.Nullable {
+				im.addReport(loc+"/parameters/"+name, "parameter schema only keeps type, format, example and $ref")
+			}
+		default:
+			im.addReport(loc+"/parameters/"+name, "dropped key "+key)
+		}
+	}
+
+	return parameter
+}
+
+func (im *Importer) convertRequestBody(body map[string]interface{}, dir, loc string) *api.RequestBody {
+	requestBody := api.NewRequestBody(nil, nil, nil, "")
+
+	for _, key := range sortedKeys(body) {
+		switch key {
+		case "description":
+			requestBody.Description = fmt.Sprint(body[key])
+		case "required":
+			requestBody.Required, _ = body[key].(bool)
+		case "content":
+			requestBody.Content = im.convertContent(asMap(body[key]), dir, loc)
+		default:
+			im.addReport(loc, "dropped key "+key)
+		}
+	}
+
+	return requestBody
+}
+
+func (im *Importer) convertResponse(code string, resp map[string]interface{}, dir, loc string) *api.Response {
+	response := api.NewResponse(nil, code, nil, nil, "")
+
+	for _, key := range sortedKeys(resp) {
+		switch key {
+		case "description":
+			response.Description = fmt.Sprint(resp[key])
+		case "content":
+			response.Content = im.convertContent(asMap(resp[key]), dir, loc)
+		default:
+			im.addReport(loc, "dropped key "+key)
+		}
+	}
+
+	return response
+}
+
+func (im *Importer) convertContent(content map[string]interface{}, dir, loc string) map[string]*api.MediaType {
+	out := make(map[string]*api.MediaType)
+	for _, mediaType := range sortedKeys(content) {
+		media := asMap(content[mediaType])
+		mt := &api.MediaType{}
+		for _, key := range sortedKeys(media) {
+			switch key {
+			case "schema":
+				mt.Schema = im.toProperty(media[key], dir, loc+"/content/"+mediaType)
+			default:
+				im.addReport(loc+"/content/"+mediaType, "dropped key "+key)
+			}
+		}
+		out[mediaType] = mt
+	}
+	return out
+}
+
+// toProperty converts a schema object, rewriting $refs relative to fromDir
+func (im *Importer) toProperty(node interface{}, fromDir, loc string) *handler.Property {
+	prop := &handler.Property{}
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		im.addReport(loc, "schema is not an object")
+		return prop
+	}
+
+	for _, key := range sortedKeys(m) {
+		value := m[key]
+		switch key {
+		case KEY_REF:
+			prop.Ref = im.rewriteRef(fmt.Sprint(value), fromDir, loc)
+		case "type":
+			// OpenAPI 3.1 style type lists such as [string, "null"]
+			if list, ok := value.([]interface{}); ok {
+				for _, t := range list {
+					if t == NULL {
+						prop.Nullable = true
+					} else if prop.Type == "" {
+						prop.Type = fmt.Sprint(t)
+					} else {
+						im.addReport(loc, "multiple types are not supported, kept "+prop.Type)
+					}
+				}
+				continue
+			}
+			prop.Type = fmt.Sprint(value)
+		case "format":
+			prop.Format = fmt.Sprint(value)
+		case "description":
+			prop.Description = fmt.Sprint(value)
+		case "enum":
+			prop.Enum = asSlice(value)
+		case "nullable":
+			prop.Nullable, _ = value.(bool)
+		case "readOnly":
+			prop.ReadOnly, _ = value.(bool)
+		case "deprecated":
+			prop.Deprecated, _ = value.(bool)
+		case "maxLength":
+			if n, ok := value.(int); ok {
+				prop.MaxLength = n
+			}
+		case "minLength":
+			if n, ok := value.(int); ok {
+				prop.MinLength = n
+			}
+		case "pattern":
+			prop.Pattern = fmt.Sprint(value)
+		case "minimum":
+			if n, ok := asNumber(value); ok {
+				prop.Minimum = &n
+			}
+		case "maximum":
+			if n, ok := asNumber(value); ok {
+				prop.Maximum = &n
+			}
+		case "minItems":
+			if n, ok := value.(int); ok {
+				prop.MinItems = n
+			}
+		case "maxItems":
+			if n, ok := value.(int); ok {
+				prop.MaxItems = n
+			}
+		case "example":
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+				im.addReport(loc, "non scalar example is not kept")
+			default:
+				prop.Example = fmt.Sprint(value)
+			}
+		case "required":
+			for _, r := range asSlice(value) {
+				prop.Required = append(prop.Required, fmt.Sprint(r))
+			}
+		case "properties":
+			props := asMap(value)
+			prop.Properties = make(map[string]*handler.Property, len(props))
+			for _, name := range sortedKeys(props) {
+				prop.Properties[name] = im.toProperty(props[name], fromDir, loc+"/properties/"+name)
+			}
+		case "items":
+			prop.Items = im.toProperty(value, fromDir, loc+"/items")
+		case "allOf":
+			// allOf with a single $ref is a common wrapper to put siblings next to a $ref
+			if list := asSlice(value); len(list) == 1 {
+				if sub := im.toProperty(list[0], fromDir, loc+"/allOf"); sub.Ref != "" {
+					prop.Ref = sub.Ref
+					continue
+				}
+			}
+			im.addReport(loc, "dropped keyword allOf")
+		default:
+			if !slices.Contains(ignoredSchemaKeys, key) {
+				im.addReport(loc, "dropped keyword "+key)
+			}
+		}
+	}
+
+	return prop
+}
+
+// rewriteRef turns an internal #/components/schemas ref into the relative file ref the fetcher builds
+func (im *Importer) rewriteRef(ref, fromDir, loc string) string {
+	if !strings.HasPrefix(ref, COMPONENTS_SCHEMAS_REF) {
+		im.addReport(loc, "unsupported $ref kept as is: "+ref)
+		return ref
+	}
+
+	rest := strings.TrimPrefix(ref, COMPONENTS_SCHEMAS_REF)
+	name, pointer, _ := strings.Cut(rest, "/")
+	name = unescapeJsonPointerToken(name)
+	if pointer != "" {
+		pointer = "/" + pointer
+	}
+
+	p, ok := im.placements[name]
+	if !ok {
+		im.addReport(loc, "$ref to unknown schema kept as is: "+ref)
+		return ref
+	}
+
+	if !p.isModel {
+		pointer = "/" + fetcher.NewBaseFetcher().EscapeJsonPointerToken(name) + pointer
+	}
+
+	rel, err := fetcher.BuildRef(fromDir, p.file, pointer)
+	if err != nil {
+		im.addReport(loc, "cannot build relative $ref: "+ref)
+		return ref
+	}
+	return rel
+}
+
+// resolveComponent inlines a $ref to a reusable component (parameter, request body, response)
+func (im *Importer) resolveComponent(node interface{}, prefix, loc string) interface{} {
+	m := asMap(node)
+	ref, ok := m[KEY_REF].(string)
+	if !ok {
+		return node
+	}
+	if !strings.HasPrefix(ref, prefix) {
+		im.addReport(loc, "unsupported $ref: "+ref)
+		return map[string]interface{}{}
+	}
+
+	section := strings.TrimSuffix(strings.TrimPrefix(prefix, "#/components/"), "/")
+	name := unescapeJsonPointerToken(strings.TrimPrefix(ref, prefix))
+	target, ok := asMap(im.lookup("components", section))[name]
+	if !ok {
+		im.addReport(loc, "$ref to unknown component: "+ref)
+		return map[string]interface{}{}
+	}
+	im.used[section+"/"+name] = true
+	return target
+}
+
+func (im *Importer) reportUnplaced() {
+	for _, key := range sortedKeys(im.doc) {
+		if !slices.Contains(topLevelPlacedKeys, key) {
+			im.addReport(key, "top-level key is not placed")
+		}
+	}
+
+	components := asMap(im.doc["components"])
+	for _, section := range sortedKeys(components) {
+		if section == "schemas" {
+			continue
+		}
+		for _, name := range sortedKeys(asMap(components[section])) {
+			if !im.used[section+"/"+name] {
+				im.addReport("components/"+section+"/"+name, "not referenced by any operation, not placed")
+			}
+		}
+	}
+}
+
+// WriteFiles writes the generated files. Existing files are kept and reported unless force is set.
+func (im *Importer) WriteFiles(force bool) ([]string, error) {
+	written := []string{}
+	for _, file := range im.Files {
+		if _, err := os.Stat(file.Path); err == nil && !force {
+			im.addReport(file.Path, "file already exists, not overwritten (use --force)")
+			continue
+		}
+		name := filepath.Base(file.Path)
+		name = name[:len(name)-len(filepath.Ext(name))]
+		if err := utils.GenerateSchema(file.Data, name, filepath.Dir(file.Path)); err != nil {
+			return written, err
+		}
+		written = append(written, file.Path)
+	}
+	return written, nil
+}
+
+func (im *Importer) addReport(loc, reason string) {
+	im.Report = append(im.Report, fmt.Sprintf("%s: %s", loc, reason))
+}
+
+func (im *Importer) lookup(keys ...string) interface{} {
+	var node interface{} = im.doc
+	for _, key := range keys {
+		node = asMap(node)[key]
+	}
+	return node
+}
+
+// normalize converts the map[interface{}]interface{} produced by yaml into map[string]interface{}
+func normalize(node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			out[fmt.Sprint(k)] = normalize(v)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(n))
+		for i, v := range n {
+			out[i] = normalize(v)
+		}
+		return out
+	default:
+		return node
+	}
+}
+
+func asMap(node interface{}) map[string]interface{} {
+	if m, ok := node.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
+
+func asNumber(node interface{}) (float64, bool) {
+	switch n := node.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func asSlice(node interface{}) []interface{} {
+	if s, ok := node.([]interface{}); ok {
+		return s
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isMethod(key string) bool {
+	for _, method := range constants.HTTPMethodsMap {
+		if method == key {
+			return true
+		}
+	}
+	return false
+}
+
+func unescapeJsonPointerToken(s string) string {
+	s = strings.ReplaceAll(s, fetcher.SLASH_ESCAPE, "/")
+	s = strings.ReplaceAll(s, fetcher.TILDE_ESCAPE, "~")
+	return s
+}
+
+// safeFileName replaces characters which are not allowed in generated file names
+func safeFileName(name string) string {
+	if schemaFileNamePattern.MatchString(name) {
+		return name
+	}
+	return model.ModelFileNameFromTable(name)
+}
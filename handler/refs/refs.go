@@ -0,0 +1,104 @@
+package refs
+
+import (
+	"fmt"
+
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+type RefsHandler struct{}
+
+func NewRefsHandler() *RefsHandler {
+	return &RefsHandler{}
+}
+
+// HandleWhoUsesCommand lists every $ref through which the location at
+// <file>#<pointer> is used: $refs to it, to an ancestor or to a part of it,
+// then the $refs reaching those through other schemas
+func (rh *RefsHandler) HandleWhoUsesCommand(arg string) error {
+	project, err := loader.LoadFromEnv()
+	if err != nil {
+		return err
+	}
+
+	target := loader.ParseTarget(arg)
+	if _, ok := project.Documents[target.File]; !ok {
+		return fmt.Errorf("[ERROR] file not found: %s", target.File)
+	}
+	if target.Pointer != "" {
+		if _, err := project.Lookup(target); err != nil {
+			return err
+		}
+	}
+
+	usages := loader.Reachable(project.References(), target, false)
+	if len(usages) == 0 {
+		fmt.Printf("[INFO] Nothing references %s.\n", target)
+		return nil
+	}
+
+	direct, indirect := []loader.Reference{}, []loader.Reference{}
+	for _, ref := range usages {
+		if target.Overlaps(ref.To) {
+			direct = append(direct, ref)
+		} else {
+			indirect = append(indirect, ref)
+		}
+	}
+	fmt.Printf("[INFO] %s is referenced by %d location(s) directly and %d through other schemas:\n", target, len(direct), len(indirect))
+	for _, ref := range direct {
+		fmt.Printf("  %s -> %s\n", ref.From, ref.To)
+	}
+	for _, ref := range indirect {
+		fmt.Printf("  %s -> %s (indirect)\n", ref.From, ref.To)
+	}
+	return nil
+}
+
+// HandleOrphansCommand lists the model and schema files and the schema roots
+// which no $ref outside of themselves points at
+func (rh *RefsHandler) HandleOrphansCommand() error {
+	project, err := loader.LoadFromEnv()
+	if err != nil {
+		return err
+	}
+	refs := project.References()
+
+	orphans := []string{}
+	for _, doc := range append(append([]*loader.Document{}, project.Models...), project.Schemas...) {
+		unused := []*loader.Root{}
+		for _, root := range doc.Roots {
+			if !isReferenced(refs, root.Target()) {
+				unused = append(unused, root)
+			}
+		}
+		if len(unused) > 0 && len(unused) == len(doc.Roots) {
+			orphans = append(orphans, doc.File)
+			continue
+		}
+		for _, root := range unused {
+			orphans = append(orphans, root.Target().String())
+		}
+	}
+
+	if len(orphans) == 0 {
+		fmt.Println("[INFO] Every model and schema is referenced.")
+		return nil
+	}
+	fmt.Printf("[INFO] %d unreferenced model(s), schema file(s) or root schema(s):\n", len(orphans))
+	for _, orphan := range orphans {
+		fmt.Printf("  %s\n", orphan)
+	}
+	return nil
+}
+
+// isReferenced reports whether a $ref from outside of the root points into it,
+// recursive schemas referencing themselves do not count
+func isReferenced(refs []loader.Reference, root loader.Target) bool {
+	for _, ref := range refs {
+		if root.Overlaps(ref.To) && !root.Contains(ref.From) {
+			return true
+		}
+	}
+	return false
+}
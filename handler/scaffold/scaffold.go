@@ -0,0 +1,150 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/input"
+	"github.com/Daaaai0809/swagen-v2/utils"
+	"github.com/Daaaai0809/swagen-v2/validator"
+)
+
+type ScaffoldHandler struct {
+	Input            input.IInputMethods
+	Validator        validator.IInputValidator
+	DirectoryFetcher fetcher.IDirectoryFetcher
+}
+
+func NewScaffoldHandler(input input.IInputMethods, validator validator.IInputValidator, directoryFetcher fetcher.IDirectoryFetcher) *ScaffoldHandler {
+	return &ScaffoldHandler{
+		Input:            input,
+		Validator:        validator,
+		DirectoryFetcher: directoryFetcher,
+	}
+}
+
+// HandleScaffoldCommand generates the model, request/response schemas and
+// collection/item path files of a CRUD resource.
+// fieldSpecs is a comma separated list of name:type[:format]; when it is empty
+// the fields are read interactively.
+func (sh *ScaffoldHandler) HandleScaffoldCommand(resource, plural, fieldSpecs string) error {
+	if err := (*sh.Validator.Validator_Alphanumeric_Underscore())(resource); err != nil {
+		return fmt.Errorf("[ERROR] invalid resource name %q: %v", resource, err)
+	}
+	if plural != "" {
+		if err := (*sh.Validator.Validator_Alphanumeric_Underscore())(plural); err != nil {
+			return fmt.Errorf("[ERROR] invalid plural name %q: %v", plural, err)
+		}
+	}
+
+	fields, err := ParseFields(fieldSpecs)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		if fields, err = sh.readFields(); err != nil {
+			return err
+		}
+	}
+	for _, f := range fields {
+		if err := (*sh.Validator.Validator_Alphanumeric_Underscore())(f.Name); err != nil {
+			return fmt.Errorf("[ERROR] invalid field name %q: %v", f.Name, err)
+		}
+	}
+
+	scaffold := NewScaffold(resource, plural, fields)
+
+	if err := sh.inputDirectories(scaffold); err != nil {
+		return err
+	}
+
+	files, err := scaffold.BuildFiles()
+	if err != nil {
+		return err
+	}
+
+	sh.preview(files)
+
+	var isWrite bool
+	if err := sh.Input.BooleanInput(&isWrite, "Write these files"); err != nil {
+		return err
+	}
+	if !isWrite {
+		return errors.New("[ERROR] scaffold canceled")
+	}
+
+	for _, file := range files {
+		name := filepath.Base(file.Path)
+		name = name[:len(name)-len(filepath.Ext(name))]
+		if err := utils.GenerateSchema(file.Data, name, filepath.Dir(file.Path)); err != nil {
+			return err
+		}
+		fmt.Printf("[INFO] Wrote %s\n", file.Path)
+	}
+
+	return nil
+}
+
+func (sh *ScaffoldHandler) readFields() ([]*Field, error) {
+	var names []string
+	if err := sh.Input.MultipleStringInput(&names, "Enter field names", sh.Validator.Validator_Alphanumeric_Underscore_Allow_Empty()); err != nil {
+		return nil, err
+	}
+
+	fields := make([]*Field, 0, len(names))
+	for _, name := range names {
+		field := &Field{Name: name}
+		if err := sh.Input.SelectInput(&field.Type, "Select Field Type ("+name+")", ScaffoldableTypes); err != nil {
+			return nil, err
+		}
+
+		if constants.IsFormatableType(field.Type) {
+			var format string
+			if err := sh.Input.SelectInput(&format, "Select Field Format ("+name+")", constants.FormatList[field.Type]); err != nil {
+				return nil, err
+			}
+			if format != constants.FORMAT_NONE {
+				field.Format = format
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func (sh *ScaffoldHandler) inputDirectories(s *Scaffold) error {
+	var err error
+
+	fmt.Println("[INFO] Select the model directory")
+	if s.ModelDirectoryPath, err = sh.DirectoryFetcher.InteractiveResolveDir(sh.Input, constants.MODE_MODEL); err != nil {
+		return err
+	}
+
+	fmt.Println("[INFO] Select the schema directory")
+	if s.SchemaDirectoryPath, err = sh.DirectoryFetcher.InteractiveResolveDir(sh.Input, constants.MODE_SCHEMA); err != nil {
+		return err
+	}
+
+	fmt.Println("[INFO] Select the path directory")
+	if s.APIDirectoryPath, err = sh.DirectoryFetcher.InteractiveResolveDir(sh.Input, constants.MODE_API); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (sh *ScaffoldHandler) preview(files []*GeneratedFile) {
+	for _, file := range files {
+		fmt.Printf("----- %s -----\n", file.Path)
+		if _, err := os.Stat(file.Path); err == nil {
+			fmt.Printf("[WARN] %s already exists and will be overwritten\n", file.Path)
+		}
+		fmt.Println(string(file.Data))
+	}
+}
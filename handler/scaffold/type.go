@@ -0,0 +1,406 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/handler/api"
+	"github.com/Daaaai0809/swagen-v2/handler/model"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ID_FIELD         = "id"
+	FIELD_SEPARATOR  = ","
+	FIELD_PART_SEP   = ":"
+	ITEM_FILE_NAME   = "{" + ID_FIELD + "}"
+	MEDIA_TYPE_JSON  = constants.APPLICATION_JSON
+	ERROR_CODE_FIELD = "code"
+	ERROR_MSG_FIELD  = "message"
+)
+
+// ScaffoldableTypes are the field types accepted for scaffolded model fields.
+// Nested objects and arrays are left to the interactive model command.
+var ScaffoldableTypes = []string{
+	constants.STRING_TYPE,
+	constants.NUMBER_TYPE,
+	constants.INTEGER_TYPE,
+	constants.BOOLEAN_TYPE,
+}
+
+type Field struct {
+	Name   string
+	Type   string
+	Format string
+}
+
+// ParseField parses a field spec written as name:type[:format] (e.g. "email:string:email")
+func ParseField(spec string) (*Field, error) {
+	parts := strings.Split(strings.TrimSpace(spec), FIELD_PART_SEP)
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("[ERROR] invalid field spec %q, expected name:type[:format]", spec)
+	}
+
+	field := &Field{Name: parts[0], Type: parts[1]}
+	if !slices.Contains(ScaffoldableTypes, field.Type) {
+		return nil, fmt.Errorf("[ERROR] unsupported type %q for field %s", field.Type, field.Name)
+	}
+
+	if len(parts) == 3 && parts[2] != "" {
+		field.Format = parts[2]
+		if !slices.Contains(constants.FormatList[field.Type], field.Format) {
+			return nil, fmt.Errorf("[ERROR] unsupported format %q for %s field %s", field.Format, field.Type, field.Name)
+		}
+	}
+
+	return field, nil
+}
+
+// ParseFields parses a comma separated list of field specs
+func ParseFields(specs string) ([]*Field, error) {
+	fields := []*Field{}
+	for _, spec := range strings.Split(specs, FIELD_SEPARATOR) {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		field, err := ParseField(spec)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// GeneratedFile is a file which is written by the scaffold command
+type GeneratedFile struct {
+	Path string
+	Data []byte
+}
+
+type Scaffold struct {
+	Resource string // singular resource name used for the model file (e.g. user)
+	Plural   string // plural resource name used for path files (e.g. users)
+	Fields   []*Field
+
+	ModelDirectoryPath  string
+	SchemaDirectoryPath string
+	APIDirectoryPath    string
+}
+
+func NewScaffold(resource, plural string, fields []*Field) *Scaffold {
+	if plural == "" {
+		plural = Pluralize(resource)
+	}
+
+	// every resource is addressed by its id, so make sure one exists
+	if !slices.ContainsFunc(fields, func(f *Field) bool { return f.Name == ID_FIELD }) {
+		fields = append([]*Field{{Name: ID_FIELD, Type: constants.STRING_TYPE, Format: constants.FORMAT_UUID}}, fields...)
+	}
+
+	return &Scaffold{
+		Resource: resource,
+		Plural:   plural,
+		Fields:   fields,
+	}
+}
+
+func (s *Scaffold) Title() string {
+	return Capitalize(s.Resource)
+}
+
+func (s *Scaffold) CreateSchemaName() string {
+	return "Create" + s.Title() + "Request"
+}
+
+func (s *Scaffold) UpdateSchemaName() string {
+	return "Update" + s.Title() + "Request"
+}
+
+func (s *Scaffold) ResponseSchemaName() string {
+	return s.Title() + "Response"
+}
+
+func (s *Scaffold) ModelFilePath() string {
+	return filepath.Join(s.ModelDirectoryPath, s.Resource+fetcher.YAML_EXT)
+}
+
+func (s *Scaffold) SchemaFilePath(name string) string {
+	return filepath.Join(s.SchemaDirectoryPath, name+fetcher.YAML_EXT)
+}
+
+func (s *Scaffold) CollectionFilePath() string {
+	return filepath.Join(s.APIDirectoryPath, s.Plural+fetcher.YAML_EXT)
+}
+
+func (s *Scaffold) ItemFilePath() string {
+	return filepath.Join(s.APIDirectoryPath, s.Plural, ITEM_FILE_NAME+fetcher.YAML_EXT)
+}
+
+// BuildFiles builds every file of the resource without writing them
+func (s *Scaffold) BuildFiles() ([]*GeneratedFile, error) {
+	if s.ModelDirectoryPath == "" || s.SchemaDirectoryPath == "" || s.APIDirectoryPath == "" {
+		return nil, errors.New("[ERROR] output directories are not resolved")
+	}
+
+	builders := []func() (*GeneratedFile, error){
+		s.buildModel,
+		s.buildCreateSchema,
+		s.buildUpdateSchema,
+		s.buildResponseSchema,
+		s.buildCollectionPath,
+		s.buildItemPath,
+	}
+
+	files := make([]*GeneratedFile, 0, len(builders))
+	for _, build := range builders {
+		file, err := build()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func (s *Scaffold) buildModel() (*GeneratedFile, error) {
+	m := model.NewModel(nil, nil, nil)
+	m.Title = s.Title()
+	for _, f := range s.Fields {
+		m.Properties[f.Name] = &handler.Property{Type: f.Type, Format: f.Format, ReadOnly: f.Name == ID_FIELD}
+	}
+
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GeneratedFile{Path: s.ModelFilePath(), Data: data}, nil
+}
+
+// fieldRef builds a $ref from destBase to a field of the scaffolded model
+func (s *Scaffold) fieldRef(destBase, fieldName string) (string, error) {
+	pointer := fetcher.PROPERTIES_PATH + "/" + fetcher.NewBaseFetcher().EscapeJsonPointerToken(fieldName)
+	return fetcher.BuildRef(destBase, s.ModelFilePath(), pointer)
+}
+
+func (s *Scaffold) buildSchema(name string, includeID bool, required bool) (*GeneratedFile, error) {
+	root := &handler.Property{
+		Type:       constants.OBJECT_TYPE,
+		Properties: make(map[string]*handler.Property),
+	}
+
+	for _, f := range s.Fields {
+		if f.Name == ID_FIELD && !includeID {
+			continue
+		}
+		ref, err := s.fieldRef(s.SchemaDirectoryPath, f.Name)
+		if err != nil {
+			return nil, err
+		}
+		root.Properties[f.Name] = &handler.Property{Ref: ref}
+		if required {
+			root.Required = append(root.Required, f.Name)
+		}
+	}
+
+	data, err := yaml.Marshal(map[string]*handler.Property{name: root})
+	if err != nil {
+		return nil, err
+	}
+
+	return &GeneratedFile{Path: s.SchemaFilePath(name), Data: data}, nil
+}
+
+func (s *Scaffold) buildCreateSchema() (*GeneratedFile, error) {
+	return s.buildSchema(s.CreateSchemaName(), false, true)
+}
+
+func (s *Scaffold) buildUpdateSchema() (*GeneratedFile, error) {
+	return s.buildSchema(s.UpdateSchemaName(), false, false)
+}
+
+func (s *Scaffold) buildResponseSchema() (*GeneratedFile, error) {
+	return s.buildSchema(s.ResponseSchemaName(), true, true)
+}
+
+// schemaRef builds a $ref from destBase to the root of a scaffolded schema
+func (s *Scaffold) schemaRef(destBase, name string) (*handler.Property, error) {
+	ref, err := fetcher.BuildRef(destBase, s.SchemaFilePath(name), "/"+name)
+	if err != nil {
+		return nil, err
+	}
+	return &handler.Property{Ref: ref}, nil
+}
+
+func (s *Scaffold) newOperation(operationID, summary string) *api.API {
+	operation := api.NewAPI(nil, nil, nil, nil)
+	operation.OperationID = operationID
+	operation.Summary = summary
+	operation.Tags = []string{Capitalize(s.Plural)}
+	return operation
+}
+
+func (s *Scaffold) addResponse(operation *api.API, code, description string, schema *handler.Property) {
+	response := api.NewResponse(nil, code, nil, nil, "")
+	response.Description = description
+	if schema != nil {
+		response.Content[MEDIA_TYPE_JSON] = &api.MediaType{Schema: schema}
+	}
+	operation.Responses[code] = response
+}
+
+func (s *Scaffold) addErrorResponses(operation *api.API, codes ...string) {
+	for _, code := range codes {
+		s.addResponse(operation, code, "error response", errorSchema())
+	}
+}
+
+func (s *Scaffold) setRequestBody(operation *api.API, schema *handler.Property) {
+	body := api.NewRequestBody(nil, nil, nil, "")
+	body.Description = "request body"
+	body.Required = true
+	body.Content[MEDIA_TYPE_JSON] = &api.MediaType{Schema: schema}
+	operation.RequestBody = body
+}
+
+func (s *Scaffold) buildCollectionPath() (*GeneratedFile, error) {
+	dir := filepath.Dir(s.CollectionFilePath())
+	title := s.Title()
+
+	response, err := s.schemaRef(dir, s.ResponseSchemaName())
+	if err != nil {
+		return nil, err
+	}
+	create, err := s.schemaRef(dir, s.CreateSchemaName())
+	if err != nil {
+		return nil, err
+	}
+
+	list := s.newOperation("list"+Capitalize(s.Plural), "list "+s.Plural)
+	s.addResponse(list, constants.SUCESS_STATUS_CODE, "success response", &handler.Property{
+		Type:  constants.ARRAY_TYPE,
+		Items: response,
+	})
+	s.addErrorResponses(list, constants.DEFAULT_STATUS)
+
+	post := s.newOperation("create"+title, "create "+s.Resource)
+	s.setRequestBody(post, create)
+	s.addResponse(post, constants.CREATED_STATUS_CODE, "created response", response)
+	s.addErrorResponses(post, constants.BAD_REQUEST_STATUS_CODE, constants.DEFAULT_STATUS)
+
+	return s.marshalPath(s.CollectionFilePath(), api.APIMap{
+		constants.HTTPMethodsMap[constants.HTTP_GET]:  list,
+		constants.HTTPMethodsMap[constants.HTTP_POST]: post,
+	})
+}
+
+func (s *Scaffold) buildItemPath() (*GeneratedFile, error) {
+	dir := filepath.Dir(s.ItemFilePath())
+	title := s.Title()
+
+	response, err := s.schemaRef(dir, s.ResponseSchemaName())
+	if err != nil {
+		return nil, err
+	}
+	create, err := s.schemaRef(dir, s.CreateSchemaName())
+	if err != nil {
+		return nil, err
+	}
+	update, err := s.schemaRef(dir, s.UpdateSchemaName())
+	if err != nil {
+		return nil, err
+	}
+	idRef, err := s.fieldRef(dir, ID_FIELD)
+	if err != nil {
+		return nil, err
+	}
+
+	newIDParameter := func() *api.Parameter {
+		return &api.Parameter{
+			In:       constants.PARAM_IN_PATH,
+			Name:     ID_FIELD,
+			Required: true,
+			Schema:   &api.ParamSchema{Ref: idRef},
+		}
+	}
+
+	get := s.newOperation("get"+title, "get "+s.Resource)
+	get.Parameters = append(get.Parameters, newIDParameter())
+	s.addResponse(get, constants.SUCESS_STATUS_CODE, "success response", response)
+	s.addErrorResponses(get, constants.NOT_FOUND_STATUS_CODE, constants.DEFAULT_STATUS)
+
+	put := s.newOperation("replace"+title, "replace "+s.Resource)
+	put.Parameters = append(put.Parameters, newIDParameter())
+	// PUT replaces the whole resource, so it takes the same required fields as a create
+	s.setRequestBody(put, create)
+	s.addResponse(put, constants.SUCESS_STATUS_CODE, "success response", response)
+	s.addErrorResponses(put, constants.BAD_REQUEST_STATUS_CODE, constants.NOT_FOUND_STATUS_CODE, constants.DEFAULT_STATUS)
+
+	patch := s.newOperation("update"+title, "update "+s.Resource)
+	patch.Parameters = append(patch.Parameters, newIDParameter())
+	s.setRequestBody(patch, update)
+	s.addResponse(patch, constants.SUCESS_STATUS_CODE, "success response", response)
+	s.addErrorResponses(patch, constants.BAD_REQUEST_STATUS_CODE, constants.NOT_FOUND_STATUS_CODE, constants.DEFAULT_STATUS)
+
+	del := s.newOperation("delete"+title, "delete "+s.Resource)
+	del.Parameters = append(del.Parameters, newIDParameter())
+	s.addResponse(del, constants.NO_CONTENT_STATUS_CODE, "deleted response", nil)
+	s.addErrorResponses(del, constants.NOT_FOUND_STATUS_CODE, constants.DEFAULT_STATUS)
+
+	return s.marshalPath(s.ItemFilePath(), api.APIMap{
+		constants.HTTPMethodsMap[constants.HTTP_GET]:    get,
+		constants.HTTPMethodsMap[constants.HTTP_PUT]:    put,
+		constants.HTTPMethodsMap[constants.HTTP_PATCH]:  patch,
+		constants.HTTPMethodsMap[constants.HTTP_DELETE]: del,
+	})
+}
+
+func (s *Scaffold) marshalPath(path string, apiMap api.APIMap) (*GeneratedFile, error) {
+	data, err := apiMap.ToYaml()
+	if err != nil {
+		return nil, err
+	}
+	return &GeneratedFile{Path: path, Data: data}, nil
+}
+
+// errorSchema is the inline error body used by the generated error responses
+func errorSchema() *handler.Property {
+	return &handler.Property{
+		Type: constants.OBJECT_TYPE,
+		Properties: map[string]*handler.Property{
+			ERROR_CODE_FIELD: {Type: constants.INTEGER_TYPE},
+			ERROR_MSG_FIELD:  {Type: constants.STRING_TYPE},
+		},
+		Required: []string{ERROR_CODE_FIELD, ERROR_MSG_FIELD},
+	}
+}
+
+// Capitalize upper-cases the first letter (user -> User)
+func Capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// Pluralize returns a naive English plural of a resource name
+func Pluralize(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return s + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return s[:len(s)-1] + "ies"
+	default:
+		return s + "s"
+	}
+}
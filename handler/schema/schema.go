@@ -45,14 +45,26 @@ func (sh *SchemaHandler) HandleGenerateSchemaCommand() error {
 		return err
 	}
 
-	if err := schema.InputPropertyNames(); err != nil {
+	var generateMode string
+	if err := sh.Input.SelectInput(&generateMode, "How do you want to define the properties", GenerateModes); err != nil {
 		return err
 	}
 
-	for _, prop := range schema.Properties {
-		if err := prop.ReadAll(); err != nil {
+	switch generateMode {
+	case GENERATE_MODE_DERIVE:
+		if err := schema.DeriveFromModel(); err != nil {
 			return err
 		}
+	default:
+		if err := schema.InputPropertyNames(); err != nil {
+			return err
+		}
+
+		for _, prop := range schema.Properties {
+			if err := prop.ReadAll(); err != nil {
+				return err
+			}
+		}
 	}
 
 	if err := schema.GenerateSchema(fileName, schemaName); err != nil {
@@ -1,15 +1,42 @@
 package schema
 
 import (
+	"fmt"
+	"os"
+	"sort"
+
 	"github.com/Daaaai0809/swagen-v2/constants"
 	"github.com/Daaaai0809/swagen-v2/fetcher"
 	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/handler/model"
 	"github.com/Daaaai0809/swagen-v2/input"
 	"github.com/Daaaai0809/swagen-v2/utils"
 	"github.com/Daaaai0809/swagen-v2/validator"
 	"gopkg.in/yaml.v2"
 )
 
+const (
+	GENERATE_MODE_MANUAL = "Enter properties manually"
+	GENERATE_MODE_DERIVE = "Derive from a model"
+
+	DERIVE_PRESET_SELECT = "Select fields"
+	DERIVE_PRESET_ALL    = "All fields"
+	DERIVE_PRESET_CREATE = "All except readOnly (create request)"
+	DERIVE_PRESET_PATCH  = "All optional (PATCH body)"
+)
+
+var GenerateModes = []string{
+	GENERATE_MODE_MANUAL,
+	GENERATE_MODE_DERIVE,
+}
+
+var DerivePresets = []string{
+	DERIVE_PRESET_SELECT,
+	DERIVE_PRESET_ALL,
+	DERIVE_PRESET_CREATE,
+	DERIVE_PRESET_PATCH,
+}
+
 type SchemaName string
 
 type Schema struct {
@@ -56,6 +83,92 @@ func (s *Schema) InputPropertyNames() error {
 	return nil
 }
 
+// DeriveFromModel lets the user pick a model and its fields, and adds a $ref
+// to each picked field instead of walking the ref picker once per property
+func (s *Schema) DeriveFromModel() error {
+	modelFile, err := s.FileFetcher.FetchModelFile(s.Input)
+	if err != nil {
+		return err
+	}
+
+	m, err := parseModelFile(modelFile)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(m.Properties))
+	writableNames := make([]string, 0, len(m.Properties))
+	for name, prop := range m.Properties {
+		names = append(names, name)
+		if !prop.ReadOnly {
+			writableNames = append(writableNames, name)
+		}
+	}
+	sort.Strings(names)
+	sort.Strings(writableNames)
+
+	var preset string
+	if err := s.Input.SelectInput(&preset, "Select fields to derive", DerivePresets); err != nil {
+		return err
+	}
+
+	var fields []string
+	isReadRequired := true
+	switch preset {
+	case DERIVE_PRESET_SELECT:
+		if err := s.Input.MultipleSelectInput(&fields, "Select fields", names, nil); err != nil {
+			return err
+		}
+	case DERIVE_PRESET_ALL:
+		fields = names
+	case DERIVE_PRESET_CREATE:
+		fields = writableNames
+	case DERIVE_PRESET_PATCH:
+		fields = writableNames
+		isReadRequired = false
+	}
+
+	if len(fields) == 0 {
+		return fmt.Errorf("[ERROR] no fields to derive from model: %s", modelFile)
+	}
+
+	for _, name := range fields {
+		pointer := fetcher.PROPERTIES_PATH + "/" + fetcher.NewBaseFetcher().EscapeJsonPointerToken(name)
+		ref, err := fetcher.BuildRef(s.DirectoryPath, modelFile, pointer)
+		if err != nil {
+			return err
+		}
+		s.Properties[name] = &handler.Property{Ref: ref}
+	}
+
+	if isReadRequired {
+		var required []string
+		if err := s.Input.MultipleSelectInput(&required, "Select required fields", fields, nil); err != nil {
+			return err
+		}
+		s.Required = append(s.Required, required...)
+	}
+
+	return nil
+}
+
+func parseModelFile(file string) (*model.Model, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var m model.Model
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("[ERROR] failed to parse YAML: %s", file)
+	}
+	if len(m.Properties) == 0 {
+		return nil, fmt.Errorf("[ERROR] no properties found in model: %s", file)
+	}
+
+	return &m, nil
+}
+
 func (s *Schema) InputSchemaName(name *SchemaName) error {
 	err := s.Input.StringInput((*string)(name), "Schema Name", s.Validator.Validator_Alphanumeric_Underscore())
 	if err != nil {
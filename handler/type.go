@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"sort"
 
 	"github.com/Daaaai0809/swagen-v2/constants"
 	"github.com/Daaaai0809/swagen-v2/fetcher"
@@ -19,14 +20,25 @@ type Property struct {
 	FileFetcher        fetcher.IFileFetcher `yaml:"-"`
 	DirectoryPath      string               `yaml:"-"`
 
-	Type       string               `yaml:"type,omitempty"`
-	Format     string               `yaml:"format,omitempty"`
-	Properties map[string]*Property `yaml:"properties,omitempty"`
-	Required   []string             `yaml:"required,omitempty"`
-	Nullable   bool                 `yaml:"nullable,omitempty"`
-	Items      *Property            `yaml:"items,omitempty"`
-	Example    string               `yaml:"example,omitempty"`
-	Ref        string               `yaml:"$ref,omitempty"` // Reference to another schema
+	Type        string               `yaml:"type,omitempty"`
+	Format      string               `yaml:"format,omitempty"`
+	Description string               `yaml:"description,omitempty"`
+	Enum        []interface{}        `yaml:"enum,omitempty"`
+	Properties  map[string]*Property `yaml:"properties,omitempty"`
+	Required    []string             `yaml:"required,omitempty"`
+	Nullable    bool                 `yaml:"nullable,omitempty"`
+	ReadOnly    bool                 `yaml:"readOnly,omitempty"`
+	Deprecated  bool                 `yaml:"deprecated,omitempty"`
+	MaxLength   int                  `yaml:"maxLength,omitempty"`
+	MinLength   int                  `yaml:"minLength,omitempty"`
+	Pattern     string               `yaml:"pattern,omitempty"` // RE2 syntax
+	Minimum     *float64             `yaml:"minimum,omitempty"`
+	Maximum     *float64             `yaml:"maximum,omitempty"`
+	Items       *Property            `yaml:"items,omitempty"`
+	MinItems    int                  `yaml:"minItems,omitempty"`
+	MaxItems    int                  `yaml:"maxItems,omitempty"`
+	Example     string               `yaml:"example,omitempty"`
+	Ref         string               `yaml:"$ref,omitempty"` // Reference to another schema
 }
 
 func NewProperty(input input.IInputMethods, propertyName string, parentProperty *Property, optionalProperties *Optionals, mode constants.InputMode, fileFetcher fetcher.IFileFetcher, directoryPath string) *Property {
@@ -102,6 +114,16 @@ func (s *Property) readNullable() error {
 	return nil
 }
 
+func (s *Property) readReadOnly() error {
+	label := "Is this property read-only? (" + s.PropertyName + ")"
+	err := s.Input.BooleanInput(&s.ReadOnly, label)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *Property) readRef() error {
 	ref, err := s.FileFetcher.InteractiveResolveRef(s.Input, s.Mode, s.DirectoryPath)
 	if err != nil {
@@ -117,6 +139,17 @@ func (s *Property) readRef() error {
 	s.Properties = nil
 	s.Items = nil
 	s.Nullable = false
+	s.ReadOnly = false
+	s.Deprecated = false
+	s.MaxLength = 0
+	s.MinLength = 0
+	s.Pattern = ""
+	s.Minimum = nil
+	s.Maximum = nil
+	s.MinItems = 0
+	s.MaxItems = 0
+	s.Description = ""
+	s.Enum = nil
 	s.Example = ""
 	return nil
 }
@@ -251,6 +284,12 @@ func (s *Property) ReadAll() error {
 		}
 	}
 
+	if s.isReadReadOnly() {
+		if err := s.readReadOnly(); err != nil {
+			return err
+		}
+	}
+
 	switch s.Type {
 	case constants.OBJECT_TYPE:
 		if err := s.readPropertyNames(); err != nil {
@@ -289,6 +328,62 @@ func (s *Property) ReadAll() error {
 	return nil
 }
 
+// ConfirmInferred walks a property tree whose types were inferred (e.g. from a
+// sample payload) and lets the user keep each inferred type or define the property again
+func (s *Property) ConfirmInferred() error {
+	isKeep := true
+	label := "Keep inferred type " + s.describeType() + " (" + s.PropertyName + ")"
+	if err := s.Input.BooleanInput(&isKeep, label); err != nil {
+		return err
+	}
+
+	if !isKeep {
+		s.Type = ""
+		s.Format = ""
+		s.Nullable = false
+		s.Properties = make(map[string]*Property)
+		s.Items = nil
+		return s.ReadAll()
+	}
+
+	switch s.Type {
+	case constants.OBJECT_TYPE:
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if err := s.Properties[name].ConfirmInferred(); err != nil {
+				return err
+			}
+		}
+	case constants.ARRAY_TYPE:
+		if s.Items != nil {
+			if err := s.Items.ConfirmInferred(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Property) describeType() string {
+	desc := s.Type
+	if s.Format != "" {
+		desc += "/" + s.Format
+	}
+	if s.Type == constants.ARRAY_TYPE && s.Items != nil {
+		desc += " of " + s.Items.describeType()
+	}
+	if s.Nullable {
+		desc += ", nullable"
+	}
+	return desc
+}
+
 func (p *Property) isReadRequired() bool {
 	if p.ParentProperty == nil {
 		return false
@@ -305,6 +400,11 @@ func (p *Property) isReadNullable() bool {
 	return p.Mode != constants.MODE_API
 }
 
+// readOnly is only asked on top-level model fields (e.g. id, createdAt)
+func (p *Property) isReadReadOnly() bool {
+	return p.Mode == constants.MODE_MODEL && p.ParentProperty == nil
+}
+
 func (p *Property) isReadRef() bool {
 	if p.Mode == constants.MODE_API {
 		return true
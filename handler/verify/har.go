@@ -0,0 +1,110 @@
+package verify
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const HAR_BASE64_ENCODING = "base64"
+
+// HAR is an HTTP Archive as exported by browsers and proxies, only the fields
+// needed to replay the traffic against the spec are read
+type HAR struct {
+	Log struct {
+		Entries []*HAREntry `json:"entries"`
+	} `json:"log"`
+}
+
+type HAREntry struct {
+	Request  HARRequest  `json:"request"`
+	Response HARResponse `json:"response"`
+}
+
+type HARRequest struct {
+	Method   string         `json:"method"`
+	URL      string         `json:"url"`
+	Headers  []HARNameValue `json:"headers"`
+	Cookies  []HARNameValue `json:"cookies"`
+	PostData *struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+	} `json:"postData"`
+}
+
+type HARResponse struct {
+	Status  int            `json:"status"`
+	Headers []HARNameValue `json:"headers"`
+	Content struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+		Encoding string `json:"encoding"`
+	} `json:"content"`
+}
+
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func ReadHAR(path string) (*HAR, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] failed to read %s: %v", path, err)
+	}
+	har := &HAR{}
+	if err := json.Unmarshal(data, har); err != nil {
+		return nil, fmt.Errorf("[ERROR] %s is not a HAR file: %v", path, err)
+	}
+	return har, nil
+}
+
+// HTTPRequest rebuilds the recorded request and returns it with its body
+func (r *HARRequest) HTTPRequest() (*http.Request, []byte, error) {
+	var body []byte
+	if r.PostData != nil {
+		body = []byte(r.PostData.Text)
+	}
+
+	req, err := http.NewRequest(r.Method, r.URL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, header := range r.Headers {
+		// HTTP/2 pseudo headers like :authority
+		if strings.HasPrefix(header.Name, ":") {
+			continue
+		}
+		req.Header.Add(header.Name, header.Value)
+	}
+	if req.Header.Get("Cookie") == "" {
+		for _, cookie := range r.Cookies {
+			req.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+		}
+	}
+	if r.PostData != nil && r.PostData.MimeType != "" {
+		req.Header.Set("Content-Type", r.PostData.MimeType)
+	}
+	return req, body, nil
+}
+
+// Body returns the recorded response body, decoding base64 content
+func (r *HARResponse) Body() ([]byte, error) {
+	if r.Content.Encoding == HAR_BASE64_ENCODING {
+		return base64.StdEncoding.DecodeString(r.Content.Text)
+	}
+	return []byte(r.Content.Text), nil
+}
+
+// ContentType returns the recorded Content-Type of the response
+func (r *HARResponse) ContentType() string {
+	for _, header := range r.Headers {
+		if strings.EqualFold(header.Name, "Content-Type") {
+			return header.Value
+		}
+	}
+	return r.Content.MimeType
+}
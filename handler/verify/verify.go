@@ -0,0 +1,173 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/loader"
+	"github.com/Daaaai0809/swagen-v2/validator/contract"
+	"github.com/Daaaai0809/swagen-v2/validator/schema"
+)
+
+const (
+	FORMAT_TEXT = "text"
+	FORMAT_JSON = "json"
+
+	// KIND_INVALID_ENTRY is a recorded entry which cannot be replayed
+	KIND_INVALID_ENTRY = "invalid-entry"
+)
+
+// Report is the result of verifying recorded traffic against the spec
+type Report struct {
+	Entries    int              `json:"entries"`
+	Matched    int              `json:"matched"` // entries matched to an operation
+	Violations []EntryViolation `json:"violations"`
+}
+
+// EntryViolation is a violation found in a recorded request or response
+type EntryViolation struct {
+	Entry  int    `json:"entry"` // index in log.entries
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	contract.Violation
+}
+
+type VerifyHandler struct {
+	BasePath string // prefix of the recorded URL paths which is not part of the path files, e.g. /api/v1
+	Format   string
+}
+
+func NewVerifyHandler(basePath, format string) *VerifyHandler {
+	return &VerifyHandler{
+		BasePath: basePath,
+		Format:   format,
+	}
+}
+
+// HandleVerifyCommand checks every request and response of a HAR file
+// against the operations of the path files. It returns an error when any
+// violation is found, so the command fails in CI.
+func (vh *VerifyHandler) HandleVerifyCommand(harPath string) error {
+	if vh.Format != FORMAT_TEXT && vh.Format != FORMAT_JSON {
+		return fmt.Errorf("[ERROR] unknown format %q: use %s or %s", vh.Format, FORMAT_TEXT, FORMAT_JSON)
+	}
+
+	har, err := ReadHAR(harPath)
+	if err != nil {
+		return err
+	}
+	project, err := loader.LoadFromEnv()
+	if err != nil {
+		return err
+	}
+	checker, err := contract.NewChecker(project)
+	if err != nil {
+		return err
+	}
+
+	report := vh.Verify(checker, har)
+	if vh.Format == FORMAT_JSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printReport(report)
+	}
+
+	if len(report.Violations) > 0 {
+		return fmt.Errorf("[ERROR] %d violation(s) found in %s", len(report.Violations), harPath)
+	}
+	return nil
+}
+
+// Verify checks every entry of a HAR file
+func (vh *VerifyHandler) Verify(checker *contract.Checker, har *HAR) *Report {
+	report := &Report{Entries: len(har.Log.Entries), Violations: []EntryViolation{}}
+	for i, entry := range har.Log.Entries {
+		violations, matched := vh.verifyEntry(checker, entry)
+		if matched {
+			report.Matched++
+		}
+		for _, v := range violations {
+			report.Violations = append(report.Violations, EntryViolation{
+				Entry:     i,
+				Method:    strings.ToUpper(entry.Request.Method),
+				URL:       entry.Request.URL,
+				Violation: v,
+			})
+		}
+	}
+	return report
+}
+
+func (vh *VerifyHandler) verifyEntry(checker *contract.Checker, entry *HAREntry) ([]contract.Violation, bool) {
+	req, body, err := entry.Request.HTTPRequest()
+	if err != nil {
+		return []contract.Violation{{Kind: KIND_INVALID_ENTRY, Location: "request", Violation: message(err.Error())}}, false
+	}
+
+	op, params, violation := checker.MatchUnder(req.Method, vh.BasePath, req.URL.Path)
+	if violation != nil {
+		return []contract.Violation{*violation}, false
+	}
+
+	violations := checker.CheckRequest(op, params, req, body)
+	// status 0 is a request without a response, e.g. an aborted one
+	if entry.Response.Status != 0 {
+		responseBody, err := entry.Response.Body()
+		if err != nil {
+			violations = append(violations, contract.Violation{
+				Kind:      KIND_INVALID_ENTRY,
+				Location:  fmt.Sprintf("response %d", entry.Response.Status),
+				Violation: message("cannot decode the recorded body: " + err.Error()),
+			})
+		} else {
+			violations = append(violations, checker.CheckResponse(op, entry.Response.Status, entry.Response.ContentType(), responseBody)...)
+		}
+	}
+	return violations, true
+}
+
+func printReport(report *Report) {
+	if len(report.Violations) == 0 {
+		fmt.Printf("[INFO] %d request(s) checked, %d matched, no violations.\n", report.Entries, report.Matched)
+		return
+	}
+
+	counts := map[string]int{}
+	entry := -1
+	for _, v := range report.Violations {
+		counts[v.Kind]++
+		if v.Entry != entry {
+			entry = v.Entry
+			fmt.Printf("[WARN] #%d %s %s\n", v.Entry, v.Method, v.URL)
+		}
+		fmt.Printf("  - %s\n", v.Violation)
+	}
+
+	fmt.Printf("[INFO] %d request(s) checked, %d matched, %d violation(s):", report.Entries, report.Matched, len(report.Violations))
+	for _, kind := range []string{
+		KIND_INVALID_ENTRY,
+		contract.KIND_UNDOCUMENTED_ENDPOINT,
+		contract.KIND_UNDOCUMENTED_METHOD,
+		contract.KIND_UNDOCUMENTED_STATUS,
+		contract.KIND_MEDIA_TYPE,
+		contract.KIND_PARAMETER,
+		contract.KIND_REQUEST_BODY,
+		contract.KIND_RESPONSE_BODY,
+	} {
+		if counts[kind] > 0 {
+			fmt.Printf(" %s %d", kind, counts[kind])
+		}
+	}
+	fmt.Println()
+}
+
+func message(text string) schema.Violation {
+	return schema.Violation{Message: text}
+}
@@ -0,0 +1,437 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/handler/api"
+	"github.com/Daaaai0809/swagen-v2/handler/model"
+	"github.com/Daaaai0809/swagen-v2/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	KIND_MODEL  = "model"
+	KIND_SCHEMA = "schema"
+	KIND_PATH   = "path"
+)
+
+// Document is a model, schema or path file of the project
+type Document struct {
+	File  string // cleaned file path
+	Kind  string
+	Title string // model title
+
+	Roots []*Root    // model: a single root, schema: one root per top-level key
+	Paths api.APIMap // path files only
+}
+
+// Root is a top-level schema: a model or a root of a schema file
+type Root struct {
+	Document *Document
+	Name     string // schema root name, "" for models
+	Schema   *handler.Property
+}
+
+// Pointer returns the JSON pointer of the root inside its document
+func (r *Root) Pointer() string {
+	if r.Name == "" {
+		return ""
+	}
+	return "/" + fetcher.NewBaseFetcher().EscapeJsonPointerToken(r.Name)
+}
+
+func (r *Root) Target() Target {
+	return Target{File: r.Document.File, Pointer: r.Pointer()}
+}
+
+// Operation is a single method of a path file
+type Operation struct {
+	Document *Document
+	Path     string // URL path template derived from the path file location
+	Method   string // lower-case HTTP method
+	API      *api.API
+}
+
+// Project is every model, schema and path file under the SWAGEN_* directories
+type Project struct {
+	ModelRoot  string
+	SchemaRoot string
+	APIRoot    string
+
+	Documents map[string]*Document // by cleaned file path
+	Models    []*Document
+	Schemas   []*Document
+	Paths     []*Document
+}
+
+// LoadFromEnv loads the project from SWAGEN_MODEL_PATH, SWAGEN_SCHEMA_PATH and SWAGEN_API_PATH
+func LoadFromEnv() (*Project, error) {
+	return Load(
+		utils.GetEnv(utils.SWAGEN_MODEL_PATH, ""),
+		utils.GetEnv(utils.SWAGEN_SCHEMA_PATH, ""),
+		utils.GetEnv(utils.SWAGEN_API_PATH, ""),
+	)
+}
+
+// Load reads every YAML file under the model, schema and API directories
+func Load(modelRoot, schemaRoot, apiRoot string) (*Project, error) {
+	p := &Project{
+		ModelRoot:  filepath.Clean(modelRoot),
+		SchemaRoot: filepath.Clean(schemaRoot),
+		APIRoot:    filepath.Clean(apiRoot),
+		Documents:  make(map[string]*Document),
+	}
+
+	for _, dir := range []struct {
+		root string
+		kind string
+	}{
+		{p.ModelRoot, KIND_MODEL},
+		{p.SchemaRoot, KIND_SCHEMA},
+		{p.APIRoot, KIND_PATH},
+	} {
+		files, err := yamlFiles(dir.root)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if _, exists := p.Documents[file]; exists {
+				// directories may be nested in each other, the first kind wins
+				continue
+			}
+			doc, err := loadDocument(file, dir.kind)
+			if err != nil {
+				return nil, err
+			}
+			p.Documents[file] = doc
+			switch dir.kind {
+			case KIND_MODEL:
+				p.Models = append(p.Models, doc)
+			case KIND_SCHEMA:
+				p.Schemas = append(p.Schemas, doc)
+			case KIND_PATH:
+				p.Paths = append(p.Paths, doc)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+func yamlFiles(root string) ([]string, error) {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	files := []string{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != root {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		lower := strings.ToLower(d.Name())
+		if !d.IsDir() && (strings.HasSuffix(lower, fetcher.YAML_EXT) || strings.HasSuffix(lower, fetcher.YML_EXT)) {
+			files = append(files, filepath.Clean(path))
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+func loadDocument(file, kind string) (*Document, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{File: file, Kind: kind}
+	switch kind {
+	case KIND_MODEL:
+		var m model.Model
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("[ERROR] failed to parse model %s: %v", file, err)
+		}
+		doc.Title = m.Title
+		doc.Roots = []*Root{{
+			Document: doc,
+			Schema: &handler.Property{
+				Type:        m.Type,
+				Description: m.Description,
+				Properties:  m.Properties,
+				Required:    m.Required,
+			},
+		}}
+	case KIND_SCHEMA:
+		var roots map[string]*handler.Property
+		if err := yaml.Unmarshal(data, &roots); err != nil {
+			return nil, fmt.Errorf("[ERROR] failed to parse schema %s: %v", file, err)
+		}
+		names := make([]string, 0, len(roots))
+		for name := range roots {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			schema := roots[name]
+			if schema == nil {
+				schema = &handler.Property{}
+			}
+			doc.Roots = append(doc.Roots, &Root{Document: doc, Name: name, Schema: schema})
+		}
+	case KIND_PATH:
+		if err := yaml.Unmarshal(data, &doc.Paths); err != nil {
+			return nil, fmt.Errorf("[ERROR] failed to parse path %s: %v", file, err)
+		}
+	}
+
+	return doc, nil
+}
+
+// Roots returns every model and schema root, models first
+func (p *Project) Roots() []*Root {
+	roots := []*Root{}
+	for _, doc := range p.Models {
+		roots = append(roots, doc.Roots...)
+	}
+	for _, doc := range p.Schemas {
+		roots = append(roots, doc.Roots...)
+	}
+	return roots
+}
+
+// RelativePath returns the path of a document relative to the directory of
+// its kind without extension, e.g. "model/sub/address.yaml" -> "sub/address"
+func (p *Project) RelativePath(doc *Document) string {
+	base := ""
+	switch doc.Kind {
+	case KIND_MODEL:
+		base = p.ModelRoot
+	case KIND_SCHEMA:
+		base = p.SchemaRoot
+	case KIND_PATH:
+		base = p.APIRoot
+	}
+
+	rel, err := filepath.Rel(base, doc.File)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = filepath.Base(doc.File)
+	}
+	rel = filepath.ToSlash(rel)
+	return strings.TrimSuffix(rel, filepath.Ext(rel))
+}
+
+// LayoutPath returns the slash separated path of a document under a directory
+// named after its kind, e.g. "model/sub/address". Generators mirror the
+// project with it.
+func (p *Project) LayoutPath(doc *Document) string {
+	kind := doc.Kind
+	if kind == "" {
+		kind = KIND_SCHEMA
+	}
+	return kind + "/" + p.RelativePath(doc)
+}
+
+// methodOrder is the order operations of a path file are listed in
+var methodOrder = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// Operations returns every operation sorted by URL path and method
+func (p *Project) Operations() ([]*Operation, error) {
+	operations := []*Operation{}
+	for _, doc := range p.Paths {
+		template, err := utils.PathTemplateFromFile(p.APIRoot, doc.File)
+		if err != nil {
+			return nil, err
+		}
+		for _, method := range methodOrder {
+			if a, ok := doc.Paths[method]; ok && a != nil {
+				operations = append(operations, &Operation{Document: doc, Path: template, Method: method, API: a})
+			}
+		}
+	}
+
+	sort.SliceStable(operations, func(i, j int) bool {
+		return operations[i].Path < operations[j].Path
+	})
+	return operations, nil
+}
+
+// Pointer returns the JSON pointer of the operation inside its path file
+func (o *Operation) Pointer() string {
+	return "/" + o.Method
+}
+
+// RootOf returns the root containing the target and the pointer tokens below it
+func (p *Project) RootOf(t Target) (*Root, []string, error) {
+	doc, ok := p.Documents[t.File]
+	if !ok {
+		return nil, nil, fmt.Errorf("[ERROR] file not found: %s", t.File)
+	}
+
+	tokens := SplitPointer(t.Pointer)
+	switch doc.Kind {
+	case KIND_MODEL:
+		return doc.Roots[0], tokens, nil
+	case KIND_SCHEMA:
+		if len(tokens) == 0 {
+			return nil, nil, fmt.Errorf("[ERROR] pointer to a schema file must name a root: %s", t)
+		}
+		for _, root := range doc.Roots {
+			if root.Name == tokens[0] {
+				return root, tokens[1:], nil
+			}
+		}
+		return nil, nil, fmt.Errorf("[ERROR] root %s not found in %s", tokens[0], t.File)
+	default:
+		return nil, nil, fmt.Errorf("[ERROR] %s is not a model or schema file", t.File)
+	}
+}
+
+// Lookup returns the schema at the target. Targets in path files point below
+// an operation, e.g. /get/responses/200/content/application~1json/schema.
+func (p *Project) Lookup(t Target) (*handler.Property, error) {
+	if doc, ok := p.Documents[t.File]; ok && doc.Kind == KIND_PATH {
+		schema, tokens, err := operationSchema(doc, SplitPointer(t.Pointer), t)
+		if err != nil {
+			return nil, err
+		}
+		return Walk(schema, tokens, t)
+	}
+
+	root, tokens, err := p.RootOf(t)
+	if err != nil {
+		return nil, err
+	}
+	return Walk(root.Schema, tokens, t)
+}
+
+// operationSchema returns the parameter, request body or response schema the
+// pointer tokens of a path file start with and the tokens below it
+func operationSchema(doc *Document, tokens []string, t Target) (*handler.Property, []string, error) {
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("[ERROR] pointer to a path file must name a method: %s", t)
+	}
+	a, ok := doc.Paths[tokens[0]]
+	if !ok || a == nil {
+		return nil, nil, fmt.Errorf("[ERROR] method %s not found in %s", tokens[0], t.File)
+	}
+
+	tokens = tokens[1:]
+	switch {
+	case len(tokens) >= 3 && tokens[0] == "parameters" && tokens[2] == "schema":
+		for i, param := range a.Parameters {
+			if fmt.Sprint(i) == tokens[1] && param != nil {
+				return ParameterSchema(param), tokens[3:], nil
+			}
+		}
+	case len(tokens) >= 4 && tokens[0] == "requestBody" && tokens[1] == "content" && tokens[3] == "schema":
+		if a.RequestBody != nil {
+			if content := a.RequestBody.Content[tokens[2]]; content != nil && content.Schema != nil {
+				return content.Schema, tokens[4:], nil
+			}
+		}
+	case len(tokens) >= 5 && tokens[0] == "responses" && tokens[2] == "content" && tokens[4] == "schema":
+		if response := a.Responses[tokens[1]]; response != nil {
+			if content := response.Content[tokens[3]]; content != nil && content.Schema != nil {
+				return content.Schema, tokens[5:], nil
+			}
+		}
+	default:
+		return nil, nil, fmt.Errorf("[ERROR] pointer to a path file must end at a parameter, request body or response schema: %s", t)
+	}
+	return nil, nil, fmt.Errorf("[ERROR] $ref target not found: %s", t)
+}
+
+// ParameterSchema returns the schema of a parameter as a property
+func ParameterSchema(param *api.Parameter) *handler.Property {
+	if param.Schema == nil {
+		return &handler.Property{}
+	}
+	schema := &handler.Property{
+		Type:    param.Schema.Type,
+		Format:  param.Schema.Format,
+		Example: param.Schema.Example,
+		Ref:     param.Schema.Ref,
+	}
+	// max and min are only written when set, 0 means unset
+	if param.Schema.Max != 0 {
+		max := float64(param.Schema.Max)
+		schema.Maximum = &max
+	}
+	if param.Schema.Min != 0 {
+		min := float64(param.Schema.Min)
+		schema.Minimum = &min
+	}
+	return schema
+}
+
+// Walk follows pointer tokens (properties/<name>, items) below a schema
+func Walk(schema *handler.Property, tokens []string, t Target) (*handler.Property, error) {
+	current := schema
+	for i := 0; i < len(tokens); i++ {
+		if current == nil {
+			break
+		}
+		switch tokens[i] {
+		case fetcher.ITEMS_OPTION:
+			current = current.Items
+		case strings.TrimPrefix(fetcher.PROPERTIES_PATH, "/"):
+			if i+1 >= len(tokens) {
+				return nil, fmt.Errorf("[ERROR] pointer ends with properties: %s", t)
+			}
+			i++
+			current = current.Properties[tokens[i]]
+		default:
+			return nil, fmt.Errorf("[ERROR] unsupported pointer token %q: %s", tokens[i], t)
+		}
+	}
+	if current == nil {
+		return nil, fmt.Errorf("[ERROR] $ref target not found: %s", t)
+	}
+	return current, nil
+}
+
+// ResolveRef resolves a $ref found in fromFile into its target and schema
+func (p *Project) ResolveRef(fromFile, ref string) (Target, *handler.Property, error) {
+	t := ParseRef(fromFile, ref)
+	schema, err := p.Lookup(t)
+	if err != nil {
+		return t, nil, err
+	}
+	return t, schema, nil
+}
+
+// Deref follows $refs until a schema without $ref is reached.
+// It returns the schema and where it is located.
+func (p *Project) Deref(at Target, schema *handler.Property) (Target, *handler.Property, error) {
+	seen := map[Target]bool{}
+	for schema != nil && schema.Ref != "" {
+		if seen[at] {
+			return at, nil, fmt.Errorf("[ERROR] circular $ref: %s", at)
+		}
+		seen[at] = true
+
+		next, resolved, err := p.ResolveRef(at.File, schema.Ref)
+		if err != nil {
+			return at, nil, err
+		}
+		at, schema = next, resolved
+	}
+	if schema == nil {
+		return at, nil, errors.New("[ERROR] empty schema")
+	}
+	return at, schema, nil
+}
@@ -0,0 +1,70 @@
+package loader
+
+import (
+	"net/url"
+	"strings"
+)
+
+// MatchPath matches a request path against a URL path template like
+// /users/{id} and returns the values of its path parameters
+func MatchPath(template, path string) (map[string]string, bool) {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(templateSegments) != len(pathSegments) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	for i, segment := range templateSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if pathSegments[i] == "" {
+				return nil, false
+			}
+			value, err := url.PathUnescape(pathSegments[i])
+			if err != nil {
+				return nil, false
+			}
+			params[strings.Trim(segment, "{}")] = value
+			continue
+		}
+		if segment != pathSegments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// literalSegments counts the segments of a template which are not parameters
+func literalSegments(template string) int {
+	count := 0
+	for _, segment := range strings.Split(strings.Trim(template, "/"), "/") {
+		if !strings.HasPrefix(segment, "{") {
+			count++
+		}
+	}
+	return count
+}
+
+// MatchOperation finds the operation of a request by method and URL path.
+// When several templates match, the one with the most literal segments wins,
+// so /users/me is preferred over /users/{id}. pathMatched reports whether any
+// template matched the path, to tell an unknown path from an unknown method.
+func MatchOperation(operations []*Operation, method, path string) (op *Operation, params map[string]string, pathMatched bool) {
+	method = strings.ToLower(method)
+	best := -1
+	for _, candidate := range operations {
+		values, ok := MatchPath(candidate.Path, path)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if candidate.Method != method {
+			continue
+		}
+		if literals := literalSegments(candidate.Path); literals > best {
+			best = literals
+			op, params = candidate, values
+		}
+	}
+	return op, params, pathMatched
+}
@@ -0,0 +1,126 @@
+package loader
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/handler"
+)
+
+// Reference is a $ref found in a document
+type Reference struct {
+	From Target // location of the schema holding the $ref
+	To   Target // location the $ref points at
+	Ref  string // the $ref as written
+}
+
+// References returns every $ref of every model, schema and path file in file order
+func (p *Project) References() []Reference {
+	refs := []Reference{}
+	var visit func(at Target, schema *handler.Property)
+	visit = func(at Target, schema *handler.Property) {
+		if schema == nil {
+			return
+		}
+		if schema.Ref != "" {
+			refs = append(refs, Reference{From: at, To: ParseRef(at.File, schema.Ref), Ref: schema.Ref})
+		}
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			visit(at.Child("properties", name), schema.Properties[name])
+		}
+		visit(at.Child(fetcher.ITEMS_OPTION), schema.Items)
+	}
+
+	files := make([]string, 0, len(p.Documents))
+	for file := range p.Documents {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		doc := p.Documents[file]
+		for _, root := range doc.Roots {
+			visit(root.Target(), root.Schema)
+		}
+		for _, method := range methodOrder {
+			a, ok := doc.Paths[method]
+			if !ok || a == nil {
+				continue
+			}
+			at := Target{File: doc.File, Pointer: "/" + method}
+			for i, param := range a.Parameters {
+				if param != nil {
+					visit(at.Child("parameters", strconv.Itoa(i), "schema"), ParameterSchema(param))
+				}
+			}
+			if a.RequestBody != nil {
+				for _, mediaType := range sortedKeys(a.RequestBody.Content) {
+					if content := a.RequestBody.Content[mediaType]; content != nil {
+						visit(at.Child("requestBody", "content", mediaType, "schema"), content.Schema)
+					}
+				}
+			}
+			for _, code := range sortedKeys(a.Responses) {
+				response := a.Responses[code]
+				if response == nil {
+					continue
+				}
+				for _, mediaType := range sortedKeys(response.Content) {
+					if content := response.Content[mediaType]; content != nil {
+						visit(at.Child("responses", code, "content", mediaType, "schema"), content.Schema)
+					}
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// Reachable returns the references reachable from the start location
+// following $refs forward, or the references through which the start
+// location is reached when not forward. A location reaches everything below
+// it, and a $ref to a schema reaches the schemas below it.
+func Reachable(refs []Reference, start Target, forward bool) []Reference {
+	visited := []Target{start}
+	included := make([]bool, len(refs))
+	for i := 0; i < len(visited); i++ {
+		at := visited[i]
+		for j, ref := range refs {
+			if included[j] {
+				continue
+			}
+			var next Target
+			if forward && at.Contains(ref.From) {
+				next = ref.To
+			} else if !forward && at.Overlaps(ref.To) {
+				next = ref.From
+			} else {
+				continue
+			}
+			included[j] = true
+			visited = append(visited, next)
+		}
+	}
+
+	reachable := []Reference{}
+	for i, ref := range refs {
+		if included[i] {
+			reachable = append(reachable, ref)
+		}
+	}
+	return reachable
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,50 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func reference(from, to string) Reference {
+	return Reference{From: ParseTarget(from), To: ParseTarget(to), Ref: to}
+}
+
+func TestReachable(t *testing.T) {
+	refs := []Reference{
+		reference("api/users.yaml#/get/responses/200/content/application~1json/schema", "schema/user.yaml#/GetUserResponse"),
+		reference("schema/user.yaml#/GetUserResponse/properties/id", "model/user.yaml#/properties/id"),
+		reference("schema/user.yaml#/Other/properties/team", "model/team.yaml"),
+		reference("model/user.yaml#/properties/team", "model/team.yaml"),
+		reference("model/a.yaml#/properties/b", "model/b.yaml"),
+		reference("model/b.yaml#/properties/a", "model/a.yaml"),
+	}
+
+	tests := []struct {
+		name    string
+		start   string
+		forward bool
+		want    []int // indexes into refs
+	}{
+		{"operation uses its response schema and the fields below it", "api/users.yaml#/get", true, []int{0, 1}},
+		{"a field only reaches its own refs", "model/user.yaml#/properties/id", true, []int{}},
+		{"a document reaches the refs of every root", "schema/user.yaml", true, []int{1, 2}},
+		{"a $ref target is used through its referrers", "model/user.yaml#/properties/id", false, []int{0, 1}},
+		{"a document is used through refs below it", "model/user.yaml", false, []int{0, 1}},
+		{"a shared model is used by every referrer", "model/team.yaml", false, []int{2, 3}},
+		{"unused location", "api/users.yaml#/get", false, []int{}},
+		{"cycles terminate forward", "model/a.yaml", true, []int{4, 5}},
+		{"cycles terminate backward", "model/a.yaml", false, []int{4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := []Reference{}
+			for _, i := range tt.want {
+				want = append(want, refs[i])
+			}
+			if got := Reachable(refs, ParseTarget(tt.start), tt.forward); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
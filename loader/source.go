@@ -0,0 +1,100 @@
+package loader
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/utils"
+)
+
+// LoadSource loads the project of a spec tree: a directory holding the
+// SWAGEN_* directories, or a git revision of the repository of the working
+// directory, e.g. HEAD~1 or v1.2.0
+func LoadSource(source string) (*Project, error) {
+	roots := []string{
+		utils.GetEnv(utils.SWAGEN_MODEL_PATH, ""),
+		utils.GetEnv(utils.SWAGEN_SCHEMA_PATH, ""),
+		utils.GetEnv(utils.SWAGEN_API_PATH, ""),
+	}
+	for i, root := range roots {
+		if filepath.IsAbs(root) {
+			// the roots are found again below the source, so they must be relative
+			cwd, err := os.Getwd()
+			if err != nil {
+				return nil, err
+			}
+			if roots[i], err = filepath.Rel(cwd, root); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		return Load(filepath.Join(source, roots[0]), filepath.Join(source, roots[1]), filepath.Join(source, roots[2]))
+	}
+	return loadRevision(source, roots)
+}
+
+// loadRevision reads the YAML files of the roots at a git revision with git
+// show into a temporary directory and loads them from there
+func loadRevision(revision string, roots []string) (*Project, error) {
+	if _, err := git("rev-parse", "--verify", "--quiet", revision+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("[ERROR] %s is neither a directory nor a git revision", revision)
+	}
+	prefix, err := git("rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "swagen-revision-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	args := []string{"ls-tree", "-r", "--name-only", "--full-name", revision, "--"}
+	for _, root := range roots {
+		args = append(args, filepath.ToSlash(filepath.Clean(root)))
+	}
+	files, err := git(args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range strings.Split(strings.TrimSpace(string(files)), "\n") {
+		lower := strings.ToLower(file)
+		if file == "" || !(strings.HasSuffix(lower, fetcher.YAML_EXT) || strings.HasSuffix(lower, fetcher.YML_EXT)) {
+			continue
+		}
+		data, err := git("show", revision+":"+file)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	// the project is fully loaded into memory, so the directory can go
+	base := filepath.Join(dir, filepath.FromSlash(strings.TrimSpace(string(prefix))))
+	return Load(filepath.Join(base, roots[0]), filepath.Join(base, roots[1]), filepath.Join(base, roots[2]))
+}
+
+func git(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] git %s: %v %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,70 @@
+package loader
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+)
+
+// Target is the location a $ref points at: a file and a JSON pointer inside it
+type Target struct {
+	File    string
+	Pointer string // without the leading '#', "" for the whole document
+}
+
+func (t Target) String() string {
+	return t.File + fetcher.JSON_POINTER_REF + t.Pointer
+}
+
+// Child returns the target below t following the given (unescaped) tokens
+func (t Target) Child(tokens ...string) Target {
+	pointer := t.Pointer
+	for _, token := range tokens {
+		pointer += "/" + fetcher.NewBaseFetcher().EscapeJsonPointerToken(token)
+	}
+	return Target{File: t.File, Pointer: pointer}
+}
+
+// Contains reports whether other is t itself or located below it
+func (t Target) Contains(other Target) bool {
+	return t.File == other.File && (t.Pointer == other.Pointer || strings.HasPrefix(other.Pointer, t.Pointer+"/"))
+}
+
+// Overlaps reports whether one of t and other is located below the other or they are the same
+func (t Target) Overlaps(other Target) bool {
+	return t.Contains(other) || other.Contains(t)
+}
+
+// ParseRef resolves a $ref written in fromFile the same way FileFetcher builds
+// them: a path relative to the directory of fromFile followed by '#' and a pointer
+func ParseRef(fromFile, ref string) Target {
+	filePart, pointer, _ := strings.Cut(ref, fetcher.JSON_POINTER_REF)
+
+	file := filepath.Clean(fromFile)
+	if filePart != "" {
+		file = filepath.Clean(filepath.Join(filepath.Dir(fromFile), filepath.FromSlash(filePart)))
+	}
+
+	return Target{File: file, Pointer: strings.TrimSuffix(pointer, "/")}
+}
+
+// ParseTarget parses a "<file>#<pointer>" argument given on the command line
+func ParseTarget(arg string) Target {
+	file, pointer, _ := strings.Cut(arg, fetcher.JSON_POINTER_REF)
+	return Target{File: filepath.Clean(file), Pointer: strings.TrimSuffix(pointer, "/")}
+}
+
+// SplitPointer splits a JSON pointer into unescaped tokens
+func SplitPointer(pointer string) []string {
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(pointer, "/")
+	for i, token := range tokens {
+		token = strings.ReplaceAll(token, fetcher.SLASH_ESCAPE, "/")
+		tokens[i] = strings.ReplaceAll(token, fetcher.TILDE_ESCAPE, "~")
+	}
+	return tokens
+}
@@ -0,0 +1,52 @@
+package loader
+
+import "testing"
+
+func TestTargetOverlaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     Target
+		contains bool // a contains b
+		overlaps bool
+	}{
+		{"same target", Target{"model/user.yaml", "/properties/id"}, Target{"model/user.yaml", "/properties/id"}, true, true},
+		{"whole document", Target{"model/user.yaml", ""}, Target{"model/user.yaml", "/properties/id"}, true, true},
+		{"below", Target{"model/user.yaml", "/properties"}, Target{"model/user.yaml", "/properties/id"}, true, true},
+		{"above", Target{"model/user.yaml", "/properties/id"}, Target{"model/user.yaml", "/properties"}, false, true},
+		{"sibling", Target{"model/user.yaml", "/properties/id"}, Target{"model/user.yaml", "/properties/name"}, false, false},
+		{"common prefix is not a parent", Target{"model/user.yaml", "/properties/id"}, Target{"model/user.yaml", "/properties/idToken"}, false, false},
+		{"other file", Target{"model/user.yaml", ""}, Target{"model/team.yaml", ""}, false, false},
+		{"same pointer in another file", Target{"schema/a.yaml", "/User"}, Target{"schema/b.yaml", "/User"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Contains(tt.b); got != tt.contains {
+				t.Errorf("%s contains %s = %v, want %v", tt.a, tt.b, got, tt.contains)
+			}
+			if got := tt.a.Overlaps(tt.b); got != tt.overlaps {
+				t.Errorf("%s overlaps %s = %v, want %v", tt.a, tt.b, got, tt.overlaps)
+			}
+			if got := tt.b.Overlaps(tt.a); got != tt.overlaps {
+				t.Errorf("%s overlaps %s = %v, want %v", tt.b, tt.a, got, tt.overlaps)
+			}
+		})
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want Target
+	}{
+		{"model/user.yaml", Target{"model/user.yaml", ""}},
+		{"./model/user.yaml#/properties/id", Target{"model/user.yaml", "/properties/id"}},
+		{"schema/user.yaml#/GetUserResponse/", Target{"schema/user.yaml", "/GetUserResponse"}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseTarget(tt.arg); got != tt.want {
+			t.Errorf("ParseTarget(%q) = %v, want %v", tt.arg, got, tt.want)
+		}
+	}
+}
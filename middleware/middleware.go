@@ -0,0 +1,261 @@
+// Package middleware validates the requests and responses of a Go HTTP
+// service against the swagen model, schema and path files.
+//
+//	validation, err := middleware.NewFromDirectories("spec/model", "spec/schema", "spec/api", middleware.Config{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	http.ListenAndServe(":8080", validation.Handler(mux))
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/Daaaai0809/swagen-v2/handler/openapi"
+	"github.com/Daaaai0809/swagen-v2/loader"
+	"github.com/Daaaai0809/swagen-v2/validator/contract"
+	"github.com/Daaaai0809/swagen-v2/validator/schema"
+)
+
+const (
+	// MODE_ENFORCE rejects requests with violations with an RFC 7807 problem
+	MODE_ENFORCE = "enforce"
+	// MODE_REPORT only logs violations of requests and of responses
+	MODE_REPORT = "report"
+
+	// DEFAULT_MAX_BODY_BYTES is the request body limit when Config.MaxBodyBytes is 0
+	DEFAULT_MAX_BODY_BYTES = 10 << 20
+)
+
+// Config configures the middleware. The zero value enforces the spec on
+// requests and logs with the standard logger.
+type Config struct {
+	Mode string // MODE_ENFORCE or MODE_REPORT, MODE_ENFORCE when empty
+
+	// BasePath is the URL path prefix the service is mounted under which is
+	// not part of the path files, e.g. /api/v1
+	BasePath string
+
+	// AllowUndocumented passes requests which match no operation to the
+	// next handler, e.g. health checks, instead of reporting them
+	AllowUndocumented bool
+
+	// MaxBodyBytes limits the request bodies read for validation. Larger
+	// ones are rejected with 413 in enforce mode, and reported and passed on
+	// unvalidated in report mode. DEFAULT_MAX_BODY_BYTES when 0, no limit
+	// when negative.
+	MaxBodyBytes int64
+
+	// OnViolation is called with the violations of every request and
+	// response which has some. It logs them with log.Printf when nil.
+	OnViolation func(r *http.Request, violations []contract.Violation)
+}
+
+// Middleware validates requests, and responses in report mode, against the
+// operations of a project. It is safe for concurrent use.
+type Middleware struct {
+	Checker *contract.Checker
+	Config  Config
+}
+
+func New(project *loader.Project, config Config) (*Middleware, error) {
+	if config.Mode == "" {
+		config.Mode = MODE_ENFORCE
+	}
+	if config.Mode != MODE_ENFORCE && config.Mode != MODE_REPORT {
+		return nil, fmt.Errorf("[ERROR] unknown mode %q: use %s or %s", config.Mode, MODE_ENFORCE, MODE_REPORT)
+	}
+	if config.OnViolation == nil {
+		config.OnViolation = logViolations
+	}
+	if config.MaxBodyBytes == 0 {
+		config.MaxBodyBytes = DEFAULT_MAX_BODY_BYTES
+	}
+
+	checker, err := contract.NewChecker(project)
+	if err != nil {
+		return nil, err
+	}
+	return &Middleware{
+		Checker: checker,
+		Config:  config,
+	}, nil
+}
+
+// NewFromDirectories loads the model, schema and path files of the swagen directory tree
+func NewFromDirectories(modelRoot, schemaRoot, apiRoot string, config Config) (*Middleware, error) {
+	project, err := loader.Load(modelRoot, schemaRoot, apiRoot)
+	if err != nil {
+		return nil, err
+	}
+	return New(project, config)
+}
+
+// NewFromOpenAPI loads a bundled OpenAPI document (YAML or JSON). It is split
+// into a temporary directory tree the same way `swagen-v2 import` does.
+func NewFromOpenAPI(file string, config Config) (*Middleware, error) {
+	dir, err := os.MkdirTemp("", "swagen-middleware-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	modelRoot := filepath.Join(dir, "model")
+	schemaRoot := filepath.Join(dir, "schema")
+	apiRoot := filepath.Join(dir, "api")
+	importer := openapi.NewImporter(modelRoot, schemaRoot, apiRoot)
+	if err := importer.Load(file); err != nil {
+		return nil, err
+	}
+	if err := importer.Convert(); err != nil {
+		return nil, err
+	}
+	if _, err := importer.WriteFiles(true); err != nil {
+		return nil, err
+	}
+
+	// the project is fully loaded into memory, so the directory can go
+	project, err := loader.Load(modelRoot, schemaRoot, apiRoot)
+	if err != nil {
+		return nil, err
+	}
+	return New(project, config)
+}
+
+// Handler wraps next with the validation
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op, params, violation := m.Checker.MatchUnder(r.Method, m.Config.BasePath, r.URL.Path)
+		if violation != nil {
+			if !m.Config.AllowUndocumented {
+				m.reject(w, r, []contract.Violation{*violation})
+			}
+			if m.Config.AllowUndocumented || m.Config.Mode == MODE_REPORT {
+				next.ServeHTTP(w, r)
+			}
+			return
+		}
+
+		body, status, err := m.readBody(r)
+		if err != nil {
+			if m.Config.Mode == MODE_ENFORCE {
+				(&contract.Problem{Status: status, Detail: err.Error()}).Write(w)
+				return
+			}
+			// report mode never blocks: the next handler gets the part read so far and the rest unvalidated
+			m.Config.OnViolation(r, []contract.Violation{{
+				Kind:      contract.KIND_REQUEST_BODY,
+				Location:  "request body",
+				Violation: schema.Violation{Message: "not validated: " + err.Error()},
+			}})
+			r.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+			m.serveReport(next, w, r, op)
+			return
+		}
+		r.Body.Close()
+		// the next handler reads the body again
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if violations := m.Checker.CheckRequest(op, params, r, body); len(violations) > 0 {
+			m.reject(w, r, violations)
+			if m.Config.Mode == MODE_ENFORCE {
+				return
+			}
+		}
+
+		if m.Config.Mode != MODE_REPORT {
+			next.ServeHTTP(w, r)
+			return
+		}
+		m.serveReport(next, w, r, op)
+	})
+}
+
+// readBody reads the request body up to Config.MaxBodyBytes. On failure it
+// returns what was read and the status to reject the request with.
+func (m *Middleware) readBody(r *http.Request) ([]byte, int, error) {
+	var reader io.Reader = r.Body
+	if m.Config.MaxBodyBytes > 0 {
+		// one more byte tells a body of exactly the limit from a larger one
+		reader = io.LimitReader(r.Body, m.Config.MaxBodyBytes+1)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return body, http.StatusBadRequest, err
+	}
+	if m.Config.MaxBodyBytes > 0 && int64(len(body)) > m.Config.MaxBodyBytes {
+		return body, http.StatusRequestEntityTooLarge, fmt.Errorf("the body is larger than %d bytes", m.Config.MaxBodyBytes)
+	}
+	return body, http.StatusOK, nil
+}
+
+// serveReport passes the request to next and reports the violations of its response
+func (m *Middleware) serveReport(next http.Handler, w http.ResponseWriter, r *http.Request, op *loader.Operation) {
+	recorder := &responseRecorder{ResponseWriter: w}
+	next.ServeHTTP(recorder, r)
+	status := recorder.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if violations := m.Checker.CheckResponse(op, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes()); len(violations) > 0 {
+		m.Config.OnViolation(r, violations)
+	}
+}
+
+// reject reports the violations, and answers the request with a problem in enforce mode
+func (m *Middleware) reject(w http.ResponseWriter, r *http.Request, violations []contract.Violation) {
+	m.Config.OnViolation(r, violations)
+	if m.Config.Mode != MODE_ENFORCE {
+		return
+	}
+	(&contract.Problem{
+		Status:     contract.ProblemStatus(violations),
+		Detail:     fmt.Sprintf("the request does not match the API specification: %d violation(s)", len(violations)),
+		Violations: violations,
+	}).Write(w)
+}
+
+func logViolations(r *http.Request, violations []contract.Violation) {
+	for _, v := range violations {
+		log.Printf("[WARN] %s %s: %s", r.Method, r.URL.RequestURI(), v)
+	}
+}
+
+// readCloser reads from Reader and closes Closer, e.g. a partly read body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// responseRecorder passes the response through while keeping a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	if rr.status == 0 {
+		rr.status = status
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(data []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	rr.body.Write(data)
+	return rr.ResponseWriter.Write(data)
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer, e.g. to flush
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ROOT_PATH_FILE_NAME = "index"
+)
+
+// Path files are laid out under SWAGEN_API_PATH following their URL path template:
+//   /users      -> users.yaml
+//   /users/{id} -> users/{id}.yaml
+//   /           -> index.yaml
+
+// PathFileFromTemplate returns the path file of a URL path template under apiRoot
+func PathFileFromTemplate(apiRoot, template string) string {
+	rel := strings.Trim(template, "/")
+	if rel == "" {
+		rel = ROOT_PATH_FILE_NAME
+	}
+	return filepath.Join(apiRoot, filepath.FromSlash(rel)+".yaml")
+}
+
+// PathTemplateFromFile returns the URL path template of a path file under apiRoot
+func PathTemplateFromFile(apiRoot, file string) (string, error) {
+	rel, err := filepath.Rel(apiRoot, file)
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+	rel = strings.TrimSuffix(rel, filepath.Ext(rel))
+	if rel == ROOT_PATH_FILE_NAME {
+		return "/", nil
+	}
+	return "/" + rel, nil
+}
@@ -0,0 +1,322 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/generator"
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/handler/api"
+	"github.com/Daaaai0809/swagen-v2/loader"
+	"github.com/Daaaai0809/swagen-v2/validator/schema"
+)
+
+// Kinds of violations
+const (
+	KIND_UNDOCUMENTED_ENDPOINT = "undocumented-endpoint"
+	KIND_UNDOCUMENTED_METHOD   = "undocumented-method"
+	KIND_UNDOCUMENTED_STATUS   = "undocumented-status"
+	KIND_MEDIA_TYPE            = "unsupported-media-type"
+	KIND_PARAMETER             = "parameter"
+	KIND_REQUEST_BODY          = "request-body"
+	KIND_RESPONSE_BODY         = "response-body"
+)
+
+// Violation is a part of a request or response which does not match the spec
+type Violation struct {
+	Kind     string `json:"kind"`
+	Location string `json:"location"` // e.g. "query parameter limit", "request body", "response 200"
+	schema.Violation
+}
+
+func (v Violation) String() string {
+	if v.Pointer == "" {
+		return fmt.Sprintf("%s: %s", v.Location, v.Message)
+	}
+	return fmt.Sprintf("%s %s", v.Location, v.Violation)
+}
+
+// Checker checks requests and responses against the operations of a project.
+// It is safe for concurrent use.
+type Checker struct {
+	Project    *loader.Project
+	Operations []*loader.Operation
+	Validator  *schema.Validator
+}
+
+func NewChecker(project *loader.Project) (*Checker, error) {
+	operations, err := project.Operations()
+	if err != nil {
+		return nil, err
+	}
+	return &Checker{
+		Project:    project,
+		Operations: operations,
+		Validator:  schema.NewValidator(project),
+	}, nil
+}
+
+// Match finds the operation of a request by method and URL path. Without one
+// it returns an undocumented endpoint or method violation.
+func (c *Checker) Match(method, path string) (*loader.Operation, map[string]string, *Violation) {
+	op, params, pathMatched := loader.MatchOperation(c.Operations, method, path)
+	if op != nil {
+		return op, params, nil
+	}
+	if pathMatched {
+		return nil, nil, violation(KIND_UNDOCUMENTED_METHOD, strings.ToUpper(method)+" "+path, "method is not documented for the path")
+	}
+	return nil, nil, violation(KIND_UNDOCUMENTED_ENDPOINT, strings.ToUpper(method)+" "+path, "no path file matches the path")
+}
+
+// MatchUnder matches the path of a service mounted under basePath, e.g.
+// /api/v1. A path outside of it, including /api/v1users, is undocumented.
+func (c *Checker) MatchUnder(method, basePath, path string) (*loader.Operation, map[string]string, *Violation) {
+	prefix := "/" + strings.Trim(basePath, "/")
+	if prefix == "/" {
+		return c.Match(method, path)
+	}
+	rest, ok := strings.CutPrefix(path, prefix)
+	if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+		return nil, nil, violation(KIND_UNDOCUMENTED_ENDPOINT, strings.ToUpper(method)+" "+path, "the path is not under the base path "+prefix)
+	}
+	if rest == "" {
+		rest = "/"
+	}
+	return c.Match(method, rest)
+}
+
+// CheckRequest checks the parameters and the body of a request. pathParams
+// are the values Match returned, body is the request body already read.
+func (c *Checker) CheckRequest(op *loader.Operation, pathParams map[string]string, r *http.Request, body []byte) []Violation {
+	violations := c.CheckParameters(op, pathParams, r)
+	return append(violations, c.CheckRequestBody(op, r.Header.Get("Content-Type"), body)...)
+}
+
+// CheckParameters checks the path, query, header and cookie parameters of a request
+func (c *Checker) CheckParameters(op *loader.Operation, pathParams map[string]string, r *http.Request) []Violation {
+	violations := []Violation{}
+	query := r.URL.Query()
+	at := operationTarget(op)
+	for i, param := range op.API.Parameters {
+		if param == nil {
+			continue
+		}
+		location := param.In + " parameter " + param.Name
+
+		var values []string
+		switch param.In {
+		case constants.PARAM_IN_PATH:
+			if value, ok := pathParams[param.Name]; ok {
+				values = []string{value}
+			}
+		case constants.PARAM_IN_QUERY:
+			values = query[param.Name]
+		case constants.PARAM_IN_HEADER:
+			values = r.Header.Values(param.Name)
+		case constants.PARAM_IN_COOKIE:
+			if cookie, err := r.Cookie(param.Name); err == nil {
+				values = []string{cookie.Value}
+			}
+		}
+		if len(values) == 0 {
+			// path parameters are always required
+			if param.Required || param.In == constants.PARAM_IN_PATH {
+				violations = append(violations, *violation(KIND_PARAMETER, location, "missing required parameter"))
+			}
+			continue
+		}
+
+		paramAt := at.Child("parameters", strconv.Itoa(i), "schema")
+		paramSchema := loader.ParameterSchema(param)
+		value, err := c.parameterValue(values, param.In, paramAt, paramSchema)
+		if err != nil {
+			violations = append(violations, *violation(KIND_PARAMETER, location, err.Error()))
+			continue
+		}
+		for _, v := range c.Validator.ValidateSchema(value, paramAt, paramSchema) {
+			violations = append(violations, Violation{Kind: KIND_PARAMETER, Location: location, Violation: v})
+		}
+	}
+	return violations
+}
+
+// CheckRequestBody checks a request body against the schema of its media type
+func (c *Checker) CheckRequestBody(op *loader.Operation, contentType string, body []byte) []Violation {
+	requestBody := op.API.RequestBody
+	location := "request body"
+	if requestBody == nil {
+		return nil
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		if requestBody.Required {
+			return []Violation{*violation(KIND_REQUEST_BODY, location, "missing required request body")}
+		}
+		return nil
+	}
+
+	mediaType := MediaTypeFor(requestBody.Content, contentType)
+	if mediaType == "" {
+		return []Violation{*violation(KIND_MEDIA_TYPE, location, fmt.Sprintf("media type %q is not documented", contentType))}
+	}
+	at := operationTarget(op).Child("requestBody", "content", mediaType, "schema")
+	return c.checkBody(KIND_REQUEST_BODY, location, at, requestBody.Content[mediaType], mediaType, contentType, body)
+}
+
+// CheckResponse checks the status code and the body of a response
+func (c *Checker) CheckResponse(op *loader.Operation, status int, contentType string, body []byte) []Violation {
+	location := fmt.Sprintf("response %d", status)
+	code, response, ok := ResponseFor(op, status)
+	if !ok {
+		return []Violation{*violation(KIND_UNDOCUMENTED_STATUS, location, "status code is not documented")}
+	}
+	if response == nil || len(response.Content) == 0 || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	mediaType := MediaTypeFor(response.Content, contentType)
+	if mediaType == "" {
+		return []Violation{*violation(KIND_MEDIA_TYPE, location, fmt.Sprintf("media type %q is not documented", contentType))}
+	}
+	at := operationTarget(op).Child("responses", code, "content", mediaType, "schema")
+	return c.checkBody(KIND_RESPONSE_BODY, location, at, response.Content[mediaType], mediaType, contentType, body)
+}
+
+// checkBody validates a JSON body against the schema of its documented media
+// type, bodies of other media types are not checked
+func (c *Checker) checkBody(kind, location string, at loader.Target, content *api.MediaType, mediaType, contentType string, body []byte) []Violation {
+	if content == nil || content.Schema == nil {
+		return nil
+	}
+	if !generator.IsJSONMediaType(mediaType) && !generator.IsJSONMediaType(baseMediaType(contentType)) {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []Violation{*violation(kind, location, fmt.Sprintf("invalid JSON: %v", err))}
+	}
+	violations := []Violation{}
+	for _, v := range c.Validator.ValidateSchema(value, at, content.Schema) {
+		violations = append(violations, Violation{Kind: kind, Location: location, Violation: v})
+	}
+	return violations
+}
+
+// parameterValue converts the raw values of a parameter into the JSON value of its schema type
+func (c *Checker) parameterValue(values []string, in string, at loader.Target, paramSchema *handler.Property) (interface{}, error) {
+	resolvedAt, resolved, err := c.Project.Deref(at, paramSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolved.Type == constants.ARRAY_TYPE {
+		// repeated query parameters, comma separated otherwise
+		if in != constants.PARAM_IN_QUERY || len(values) == 1 {
+			values = strings.Split(strings.Join(values, ","), ",")
+		}
+		itemType := ""
+		if resolved.Items != nil {
+			_, item, err := c.Project.Deref(resolvedAt.Child(fetcher.ITEMS_OPTION), resolved.Items)
+			if err != nil {
+				return nil, err
+			}
+			itemType = item.Type
+		}
+		items := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			item, err := scalar(value, itemType)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	return scalar(values[0], resolved.Type)
+}
+
+// scalar converts a raw parameter value into a value of a primitive schema type
+func scalar(value, schemaType string) (interface{}, error) {
+	switch schemaType {
+	case constants.INTEGER_TYPE:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected integer, got %q", value)
+		}
+		return float64(n), nil
+	case constants.NUMBER_TYPE:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected number, got %q", value)
+		}
+		return n, nil
+	case constants.BOOLEAN_TYPE:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected boolean, got %q", value)
+		}
+		return b, nil
+	}
+	return value, nil
+}
+
+// ResponseFor returns the documented response of a status code: the exact
+// code, then its range like 4XX, then default
+func ResponseFor(op *loader.Operation, status int) (string, *api.Response, bool) {
+	code := strconv.Itoa(status)
+	candidates := []string{code}
+	if len(code) == 3 {
+		candidates = append(candidates, code[:1]+"XX", code[:1]+"xx")
+	}
+	candidates = append(candidates, generator.DEFAULT_RESPONSE_CODE)
+	for _, candidate := range candidates {
+		if response, ok := op.API.Responses[candidate]; ok {
+			return candidate, response, true
+		}
+	}
+	return "", nil, false
+}
+
+// MediaTypeFor returns the documented media type matching a Content-Type
+// header, trying the exact type, then type/* and */*. Without a header the
+// preferred documented media type is used.
+func MediaTypeFor[T any](content map[string]T, contentType string) string {
+	if strings.TrimSpace(contentType) == "" {
+		return generator.PreferredMediaType(content)
+	}
+	base := baseMediaType(contentType)
+	mainType, _, _ := strings.Cut(base, "/")
+	for _, candidate := range []string{base, mainType + "/*", "*/*"} {
+		for mediaType := range content {
+			if strings.EqualFold(baseMediaType(mediaType), candidate) {
+				return mediaType
+			}
+		}
+	}
+	return ""
+}
+
+// baseMediaType strips the parameters of a media type, e.g. "; charset=utf-8"
+func baseMediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	base, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(base))
+}
+
+func operationTarget(op *loader.Operation) loader.Target {
+	return loader.Target{File: op.Document.File, Pointer: op.Pointer()}
+}
+
+func violation(kind, location, message string) *Violation {
+	return &Violation{Kind: kind, Location: location, Violation: schema.Violation{Message: message}}
+}
@@ -0,0 +1,51 @@
+package contract
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	PROBLEM_CONTENT_TYPE = "application/problem+json"
+	PROBLEM_TYPE         = "about:blank"
+)
+
+// Problem is an RFC 7807 problem details body
+type Problem struct {
+	Type       string      `json:"type"`
+	Title      string      `json:"title"`
+	Status     int         `json:"status"`
+	Detail     string      `json:"detail,omitempty"`
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// ProblemStatus returns the status a request with violations is rejected
+// with: 404 and 405 for unknown endpoints and methods, 415 for undocumented
+// media types and 400 otherwise
+func ProblemStatus(violations []Violation) int {
+	for _, v := range violations {
+		switch v.Kind {
+		case KIND_UNDOCUMENTED_ENDPOINT:
+			return http.StatusNotFound
+		case KIND_UNDOCUMENTED_METHOD:
+			return http.StatusMethodNotAllowed
+		case KIND_MEDIA_TYPE:
+			return http.StatusUnsupportedMediaType
+		}
+	}
+	return http.StatusBadRequest
+}
+
+// Write writes the problem as the response, filling in the type and the title
+func (p *Problem) Write(w http.ResponseWriter) {
+	if p.Type == "" {
+		p.Type = PROBLEM_TYPE
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	data, _ := json.Marshal(p)
+	w.Header().Set("Content-Type", PROBLEM_CONTENT_TYPE)
+	w.WriteHeader(p.Status)
+	w.Write(data)
+}
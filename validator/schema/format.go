@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+)
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+)
+
+// CheckFormat checks a string against a format. Formats which only annotate
+// the value (password, binary, ...) and unknown formats always pass.
+func CheckFormat(value, format string) error {
+	valid := true
+	switch format {
+	case constants.FORMAT_DATE_TIME:
+		_, err := time.Parse(time.RFC3339, value)
+		valid = err == nil
+	case constants.FORMAT_DATE:
+		_, err := time.Parse(time.DateOnly, value)
+		valid = err == nil
+	case constants.FORMAT_EMAIL:
+		address, err := mail.ParseAddress(value)
+		valid = err == nil && address.Address == value
+	case constants.FORMAT_UUID:
+		valid = uuidPattern.MatchString(value)
+	case constants.FORMAT_HOSTNAME:
+		valid = len(value) <= 253 && hostnamePattern.MatchString(value)
+	case constants.FORMAT_IPV4:
+		ip := net.ParseIP(value)
+		valid = ip != nil && ip.To4() != nil && !strings.Contains(value, ":")
+	case constants.FORMAT_IPV6:
+		valid = net.ParseIP(value) != nil && strings.Contains(value, ":")
+	case constants.FORMAT_URI:
+		u, err := url.Parse(value)
+		valid = err == nil && u.Scheme != ""
+	case constants.FORMAT_URI_REF:
+		_, err := url.Parse(value)
+		valid = err == nil
+	case constants.FORMAT_BYTE:
+		_, err := base64.StdEncoding.DecodeString(value)
+		valid = err == nil
+	case constants.FORMAT_REGEX:
+		_, err := regexp.Compile(value)
+		valid = err == nil
+	case constants.FORMAT_JSON_POINTER:
+		valid = value == "" || strings.HasPrefix(value, "/")
+	}
+	if !valid {
+		return fmt.Errorf("must be a valid %s", format)
+	}
+	return nil
+}
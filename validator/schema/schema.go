@@ -0,0 +1,342 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Daaaai0809/swagen-v2/constants"
+	"github.com/Daaaai0809/swagen-v2/fetcher"
+	"github.com/Daaaai0809/swagen-v2/handler"
+	"github.com/Daaaai0809/swagen-v2/loader"
+)
+
+// Violation is a value which does not match its schema
+type Violation struct {
+	Pointer string `json:"pointer"` // JSON pointer of the value inside the validated instance, "" for the instance itself
+	Schema  string `json:"schema"`  // <file>#<pointer> of the schema the value was checked against
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	pointer := v.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s: %s", pointer, v.Message)
+}
+
+// Validator checks decoded JSON values (as produced by encoding/json into an
+// interface{}) against the schemas of a project, following $refs across files.
+// It is safe for concurrent use.
+type Validator struct {
+	Project *loader.Project
+
+	patterns sync.Map // pattern -> *regexp.Regexp or error
+}
+
+func NewValidator(project *loader.Project) *Validator {
+	return &Validator{
+		Project: project,
+	}
+}
+
+// Validate checks value against the schema at ref, a <file>#<pointer> like
+// model/user.yaml or schema/user.yaml#/GetUserResponse/properties/name,
+// loading the project from the SWAGEN_* directories
+func Validate(value interface{}, ref string) []Violation {
+	project, err := loader.LoadFromEnv()
+	if err != nil {
+		return []Violation{{Schema: ref, Message: err.Error()}}
+	}
+	return NewValidator(project).Validate(value, ref)
+}
+
+// Validate checks value against the schema at ref, a <file>#<pointer> of the project
+func (v *Validator) Validate(value interface{}, ref string) []Violation {
+	target := loader.ParseTarget(ref)
+	schema, err := v.Project.Lookup(target)
+	if err != nil {
+		return []Violation{{Schema: target.String(), Message: err.Error()}}
+	}
+	return v.ValidateSchema(value, target, schema)
+}
+
+// ValidateSchema checks value against schema, located at `at` so relative
+// $refs inside it resolve from its file
+func (v *Validator) ValidateSchema(value interface{}, at loader.Target, schema *handler.Property) []Violation {
+	c := &check{validator: v, following: make(map[loader.Target]bool)}
+	c.validate(value, "", at, schema)
+	return c.violations
+}
+
+// pattern returns the compiled pattern, compiling each pattern once
+func (v *Validator) pattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := v.patterns.Load(pattern); ok {
+		if re, ok := cached.(*regexp.Regexp); ok {
+			return re, nil
+		}
+		return nil, cached.(error)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		v.patterns.Store(pattern, err)
+		return nil, err
+	}
+	v.patterns.Store(pattern, re)
+	return re, nil
+}
+
+type check struct {
+	validator  *Validator
+	violations []Violation
+	following  map[loader.Target]bool // $refs being followed for the current value
+}
+
+func (c *check) report(pointer string, at loader.Target, format string, args ...interface{}) {
+	c.violations = append(c.violations, Violation{
+		Pointer: pointer,
+		Schema:  at.String(),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *check) validate(value interface{}, pointer string, at loader.Target, schema *handler.Property) {
+	if schema == nil {
+		return
+	}
+
+	if schema.Ref != "" {
+		if value == nil && schema.Nullable {
+			return
+		}
+		target, resolved, err := c.validator.Project.ResolveRef(at.File, schema.Ref)
+		if err != nil {
+			c.report(pointer, at, "cannot resolve $ref %s: %v", schema.Ref, err)
+			return
+		}
+		if c.following[target] {
+			c.report(pointer, at, "circular $ref %s", schema.Ref)
+			return
+		}
+		c.following[target] = true
+		c.validate(value, pointer, target, resolved)
+		delete(c.following, target)
+		return
+	}
+	// a new value is checked below, so $ref chains start over
+	following := c.following
+	c.following = make(map[loader.Target]bool)
+	defer func() { c.following = following }()
+
+	if value == nil {
+		if !schema.Nullable && schemaType(schema) != "" {
+			c.report(pointer, at, "must not be null")
+		}
+		return
+	}
+
+	if t := schemaType(schema); t != "" && !hasType(value, t) {
+		c.report(pointer, at, "expected %s, got %s", t, TypeOf(value))
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(value, schema.Enum) {
+		c.report(pointer, at, "must be one of %s", formatEnum(schema.Enum))
+	}
+
+	switch v := value.(type) {
+	case string:
+		c.validateString(v, pointer, at, schema)
+	case map[string]interface{}:
+		c.validateObject(v, pointer, at, schema)
+	case []interface{}:
+		if schema.MinItems > 0 && len(v) < schema.MinItems {
+			c.report(pointer, at, "must have at least %d items", schema.MinItems)
+		}
+		if schema.MaxItems > 0 && len(v) > schema.MaxItems {
+			c.report(pointer, at, "must have at most %d items", schema.MaxItems)
+		}
+		if schema.Items != nil {
+			for i, item := range v {
+				c.validate(item, fmt.Sprintf("%s/%d", pointer, i), at.Child(fetcher.ITEMS_OPTION), schema.Items)
+			}
+		}
+	default:
+		if n, ok := number(value); ok {
+			c.validateNumber(n, pointer, at, schema)
+		}
+	}
+}
+
+func (c *check) validateString(value, pointer string, at loader.Target, schema *handler.Property) {
+	length := utf8.RuneCountInString(value)
+	if schema.MinLength > 0 && length < schema.MinLength {
+		c.report(pointer, at, "must be at least %d characters long", schema.MinLength)
+	}
+	if schema.MaxLength > 0 && length > schema.MaxLength {
+		c.report(pointer, at, "must be at most %d characters long", schema.MaxLength)
+	}
+	if schema.Pattern != "" {
+		re, err := c.validator.pattern(schema.Pattern)
+		if err != nil {
+			c.report(pointer, at, "invalid pattern %q: %v", schema.Pattern, err)
+		} else if !re.MatchString(value) {
+			c.report(pointer, at, "must match the pattern %q", schema.Pattern)
+		}
+	}
+	if err := CheckFormat(value, schema.Format); err != nil {
+		c.report(pointer, at, "%v", err)
+	}
+}
+
+func (c *check) validateNumber(value float64, pointer string, at loader.Target, schema *handler.Property) {
+	if schema.Minimum != nil && value < *schema.Minimum {
+		c.report(pointer, at, "must be at least %v", *schema.Minimum)
+	}
+	if schema.Maximum != nil && value > *schema.Maximum {
+		c.report(pointer, at, "must be at most %v", *schema.Maximum)
+	}
+	switch schema.Format {
+	case constants.FORMAT_INT32:
+		if value < math.MinInt32 || value > math.MaxInt32 {
+			c.report(pointer, at, "must be a valid %s", schema.Format)
+		}
+	case constants.FORMAT_INT64:
+		if value < math.MinInt64 || value > math.MaxInt64 {
+			c.report(pointer, at, "must be a valid %s", schema.Format)
+		}
+	}
+}
+
+func (c *check) validateObject(value map[string]interface{}, pointer string, at loader.Target, schema *handler.Property) {
+	for _, name := range schema.Required {
+		if _, ok := value[name]; !ok {
+			c.report(pointer+"/"+escape(name), at, "missing required property %s", name)
+		}
+	}
+
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prop, ok := schema.Properties[name]
+		if !ok {
+			continue
+		}
+		c.validate(value[name], pointer+"/"+escape(name), at.Child(strings.TrimPrefix(fetcher.PROPERTIES_PATH, "/"), name), prop)
+	}
+}
+
+func escape(token string) string {
+	return fetcher.NewBaseFetcher().EscapeJsonPointerToken(token)
+}
+
+// schemaType is the type of a schema, objects may leave it out when they have properties
+func schemaType(schema *handler.Property) string {
+	if schema.Type == "" && len(schema.Properties) > 0 {
+		return constants.OBJECT_TYPE
+	}
+	return schema.Type
+}
+
+// number returns the value of a JSON number
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func hasType(value interface{}, schemaType string) bool {
+	switch schemaType {
+	case constants.STRING_TYPE:
+		_, ok := value.(string)
+		return ok
+	case constants.INTEGER_TYPE:
+		n, ok := number(value)
+		return ok && n == math.Trunc(n)
+	case constants.NUMBER_TYPE:
+		_, ok := number(value)
+		return ok
+	case constants.BOOLEAN_TYPE:
+		_, ok := value.(bool)
+		return ok
+	case constants.ARRAY_TYPE:
+		_, ok := value.([]interface{})
+		return ok
+	case constants.OBJECT_TYPE:
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+	return true
+}
+
+// TypeOf returns the JSON schema type of a decoded JSON value
+func TypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return constants.STRING_TYPE
+	case bool:
+		return constants.BOOLEAN_TYPE
+	case []interface{}:
+		return constants.ARRAY_TYPE
+	case map[string]interface{}:
+		return constants.OBJECT_TYPE
+	}
+	if n, ok := number(value); ok {
+		if n == math.Trunc(n) {
+			return constants.INTEGER_TYPE
+		}
+		return constants.NUMBER_TYPE
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// inEnum compares numbers by value, since YAML enums decode to ints and JSON to float64
+func inEnum(value interface{}, enum []interface{}) bool {
+	n, isNumber := number(value)
+	for _, candidate := range enum {
+		if m, ok := number(candidate); ok && isNumber {
+			if n == m {
+				return true
+			}
+			continue
+		}
+		if fmt.Sprint(candidate) == fmt.Sprint(value) && TypeOf(candidate) == TypeOf(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []interface{}) string {
+	values := make([]string, 0, len(enum))
+	for _, value := range enum {
+		if s, ok := value.(string); ok {
+			values = append(values, fmt.Sprintf("%q", s))
+			continue
+		}
+		values = append(values, fmt.Sprint(value))
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
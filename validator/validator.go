@@ -10,6 +10,7 @@ import (
 type IInputValidator interface {
 	Validator_Alphanumeric_Underscore() *input.ValidationFunc
 	Validator_Alphanumeric_Underscore_Allow_Empty() *input.ValidationFunc
+	Validator_Path_Segment() *input.ValidationFunc
 }
 
 type InputValidator struct{}
@@ -43,3 +44,17 @@ func (v *InputValidator) Validator_Alphanumeric_Underscore_Allow_Empty() *input.
 
 	return &validator
 }
+
+// Validator_Path_Segment accepts a path file name: a literal URL path segment
+// like users, or a path parameter like {id}
+func (v *InputValidator) Validator_Path_Segment() *input.ValidationFunc {
+	var validator input.ValidationFunc = func(input string) error {
+		validName := regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_-]*|\{[a-zA-Z_][a-zA-Z0-9_]*\})$`)
+		if !validName.MatchString(input) {
+			return errors.New("path file name can only be a path segment of alphanumeric characters, underscores and hyphens, or a path parameter like {id}")
+		}
+		return nil
+	}
+
+	return &validator
+}